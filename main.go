@@ -3,17 +3,63 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/rekki/reveal/reveal"
 )
 
+// stringsFlag is a repeatable string flag.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
 func main() {
-	if len(os.Args) != 2 {
-		panic("usage: reveal <pkg>")
+	var cfg reveal.Config
+	flag.Var((*stringsFlag)(&cfg.Entrypoints), "entrypoint", "package to start from, as an import path or a relative directory (repeatable)")
+	all := flag.Bool("all", false, "generate one schema per entrypoint (every main package by default), indexed by package path")
+	schemas := flag.String("schemas", "", "JSON file of the schemas overriding types, indexed by qualified name (e.g. github.com/shopspring/decimal.Decimal)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: reveal [flags] <pkg>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg.Report = func(d reveal.Diagnostic) {
+		fmt.Fprintf(os.Stderr, "reveal: %s\n", d)
+	}
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
 	}
 
-	out, err := reveal.Reveal(context.Background(), os.Args[1])
+	if *schemas != "" {
+		data, err := os.ReadFile(*schemas)
+		if err != nil {
+			panic(err)
+		}
+		if err := json.Unmarshal(data, &cfg.Schemas); err != nil {
+			panic(err)
+		}
+	}
+
+	var out interface{}
+	var err error
+	if *all {
+		out, err = reveal.RevealAll(context.Background(), flag.Arg(0), cfg)
+	} else {
+		out, err = reveal.RevealConfig(context.Background(), flag.Arg(0), cfg)
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,245 @@
+package reveal
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"golang.org/x/tools/go/packages"
+)
+
+// parser describes a well-known function parsing a string, the position of
+// that string in its arguments and the schema of what it accepts.
+type parser struct {
+	arg    int
+	schema func(call *ast.CallExpr, v *EndpointsVisitor, pkg *packages.Package) *openapi3.Schema
+}
+
+func parsedAs(schema func() *openapi3.Schema) func(*ast.CallExpr, *EndpointsVisitor, *packages.Package) *openapi3.Schema {
+	return func(*ast.CallExpr, *EndpointsVisitor, *packages.Package) *openapi3.Schema {
+		return schema()
+	}
+}
+
+// parsers are indexed by the full name of the function.
+var parsers = map[string]parser{
+	"strconv.Atoi":       {0, parsedAs(openapi3.NewIntegerSchema)},
+	"strconv.ParseInt":   {0, parsedAs(openapi3.NewIntegerSchema)},
+	"strconv.ParseUint":  {0, parsedAs(func() *openapi3.Schema { return openapi3.NewIntegerSchema().WithMin(0) })},
+	"strconv.ParseFloat": {0, parsedAs(openapi3.NewFloat64Schema)},
+	"strconv.ParseBool":  {0, parsedAs(openapi3.NewBoolSchema)},
+
+	"github.com/google/uuid.Parse":              {0, parsedAs(openapi3.NewUUIDSchema)},
+	"github.com/google/uuid.MustParse":          {0, parsedAs(openapi3.NewUUIDSchema)},
+	"github.com/gofrs/uuid.FromString":          {0, parsedAs(openapi3.NewUUIDSchema)},
+	"github.com/gofrs/uuid.FromStringOrNil":     {0, parsedAs(openapi3.NewUUIDSchema)},
+	"github.com/satori/go.uuid.FromString":      {0, parsedAs(openapi3.NewUUIDSchema)},
+	"github.com/satori/go.uuid.FromStringOrNil": {0, parsedAs(openapi3.NewUUIDSchema)},
+
+	"time.Parse": {1, func(call *ast.CallExpr, v *EndpointsVisitor, pkg *packages.Package) *openapi3.Schema {
+		layout, _ := v.foldStringConstant(call.Args[0], pkg)
+		switch layout {
+		case time.RFC3339, time.RFC3339Nano:
+			return openapi3.NewDateTimeSchema()
+		case "2006-01-02":
+			return openapi3.NewStringSchema().WithFormat("date")
+		}
+		return nil
+	}},
+}
+
+// inForm is where form fields are read from, as opposed to parameters.
+const inForm = "formData"
+
+// conversion records that a parameter is parsed into a more precise type.
+type conversion struct {
+	in     string
+	name   string
+	schema *openapi3.Schema
+}
+
+// inspectConversion records the type of the parameters read from the gin
+// context and parsed right away, e.g. strconv.Atoi(c.Param("id")).
+func (v *EndpointsVisitor) inspectConversion(callexpr *ast.CallExpr, pkg *packages.Package, info *handlerInfo) {
+	p, ok := v.parserOf(callexpr, pkg)
+	if !ok || p.arg >= len(callexpr.Args) {
+		return
+	}
+
+	in, name, ok := v.paramSource(callexpr.Args[p.arg], pkg)
+	if !ok {
+		return
+	}
+
+	if schema := p.schema(callexpr, v, pkg); schema != nil {
+		info.conversions = append(info.conversions, conversion{in, name, schema})
+	}
+}
+
+func (v *EndpointsVisitor) parserOf(callexpr *ast.CallExpr, pkg *packages.Package) (parser, bool) {
+	fn := calledFunc(callexpr, pkg)
+	if fn == nil {
+		return parser{}, false
+	}
+	p, ok := parsers[fn.FullName()]
+	return p, ok
+}
+
+// paramSource tells which parameter expr was read from, if any.
+func (v *EndpointsVisitor) paramSource(expr ast.Expr, pkg *packages.Package) (string, string, bool) {
+	expr, pkg = v.resolve(expr, pkg)
+
+	callexpr, ok := expr.(*ast.CallExpr)
+	if !ok || len(callexpr.Args) == 0 {
+		return "", "", false
+	}
+	sel, ok := callexpr.Fun.(*ast.SelectorExpr)
+	if !ok || !isGinContext(pkg.TypesInfo.TypeOf(sel.X)) {
+		return "", "", false
+	}
+
+	var in string
+	switch sel.Sel.Name {
+	case "Param":
+		in = openapi3.ParameterInPath
+	case "Query", "DefaultQuery":
+		in = openapi3.ParameterInQuery
+	case "GetHeader":
+		in = openapi3.ParameterInHeader
+	case "PostForm", "DefaultPostForm":
+		in = inForm
+	default:
+		return "", "", false
+	}
+
+	name, ok := v.foldStringConstant(callexpr.Args[0], pkg)
+	return in, name, ok
+}
+
+// applyConversions refines the parameters with the types they are parsed to.
+func (info *handlerInfo) applyConversions() {
+	for _, c := range info.conversions {
+		switch c.in {
+		case openapi3.ParameterInPath:
+			info.uriParams = append(info.uriParams, newParam(c.in, c.name, c.schema))
+
+		case inForm:
+			if info.form != nil {
+				if prev, ok := info.form.Properties[c.name]; ok && isPlainString(prev) {
+					info.form.Properties[c.name] = &openapi3.SchemaRef{Value: c.schema}
+				} else if ok && prev.Value != nil && prev.Value.Default != nil {
+					info.form.Properties[c.name] = &openapi3.SchemaRef{Value: withDefault(c.schema, prev.Value.Default)}
+				}
+			}
+
+		default:
+			// merged with the parameter as it is read
+			info.params = append(info.params, newParam(c.in, c.name, c.schema))
+		}
+	}
+}
+
+// withDefault copies schema with the given string default value, converted
+// to the type of the schema.
+func withDefault(schema *openapi3.Schema, value interface{}) *openapi3.Schema {
+	out := *schema
+	s, ok := value.(string)
+	if !ok {
+		return &out
+	}
+
+	switch out.Type {
+	case openapi3.TypeInteger:
+		if n, err := strconv.Atoi(s); err == nil {
+			out.Default = n
+		}
+	case openapi3.TypeNumber:
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			out.Default = n
+		}
+	case openapi3.TypeBoolean:
+		if b, err := strconv.ParseBool(s); err == nil {
+			out.Default = b
+		}
+	default:
+		out.Default = s
+	}
+	return &out
+}
+
+// inspectParseError records a 400 response when a parameter fails to parse
+// and the request is aborted without a known status, e.g.
+//
+//	id, err := strconv.Atoi(c.Param("id"))
+//	if err != nil {
+//		c.Abort()
+//		return
+//	}
+func (v *EndpointsVisitor) inspectParseError(assign ast.Stmt, ifstmt *ast.IfStmt, pkg *packages.Package, info *handlerInfo) {
+	stmt, ok := assign.(*ast.AssignStmt)
+	if !ok || len(stmt.Rhs) != 1 || len(stmt.Lhs) < 2 {
+		return
+	}
+	callexpr, ok := unparen(stmt.Rhs[0]).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	if p, ok := v.parserOf(callexpr, pkg); !ok || p.arg >= len(callexpr.Args) {
+		return
+	} else if _, _, ok := v.paramSource(callexpr.Args[p.arg], pkg); !ok {
+		return
+	}
+
+	errIdent, ok := stmt.Lhs[len(stmt.Lhs)-1].(*ast.Ident)
+	if !ok || !isErrNotNil(ifstmt.Cond, pkg.TypesInfo.ObjectOf(errIdent), pkg) {
+		return
+	}
+
+	aborted, known := false, false
+	ast.Inspect(ifstmt.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !isGinContext(pkg.TypesInfo.TypeOf(sel.X)) || !strings.HasPrefix(sel.Sel.Name, "Abort") {
+			return true
+		}
+		aborted = true
+		if len(call.Args) > 0 {
+			if _, ok := v.foldIntConstant(call.Args[0], pkg); ok {
+				known = true
+			}
+		}
+		return true
+	})
+
+	status := strconv.Itoa(http.StatusBadRequest)
+	if aborted && !known && info.responses[status] == nil {
+		d := "description"
+		info.responses[status] = &openapi3.ResponseRef{
+			Value: &openapi3.Response{
+				Description: &d,
+			},
+		}
+	}
+}
+
+// isErrNotNil reports whether cond is `err != nil` for the given err.
+func isErrNotNil(cond ast.Expr, err types.Object, pkg *packages.Package) bool {
+	bin, ok := unparen(cond).(*ast.BinaryExpr)
+	if !ok || bin.Op != token.NEQ {
+		return false
+	}
+	x, ok := unparen(bin.X).(*ast.Ident)
+	if !ok || pkg.TypesInfo.ObjectOf(x) != err {
+		return false
+	}
+	y, ok := unparen(bin.Y).(*ast.Ident)
+	return ok && y.Name == "nil"
+}
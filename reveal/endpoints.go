@@ -1,10 +1,15 @@
 package reveal
 
 import (
+	"fmt"
 	"go/ast"
 	"go/constant"
+	"go/token"
 	"go/types"
+	"net/http"
+	"path"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,73 +19,169 @@ import (
 )
 
 type EndpointsVisitor struct {
-	root         *Group            // root group
-	schemas      *SchemaRegistry   // hoisted schemas
-	entrypoint   *packages.Package // root package
+	root         *Group              // root group
+	schemas      *SchemaRegistry     // hoisted schemas
+	entrypoints  []*packages.Package // packages walked from their main function
 	pkgsByID     map[string]*packages.Package
 	groupsByExpr map[ast.Expr]*Group
-	exprsByIdent map[ast.Object]ast.Expr
+	groupsByObj  map[types.Object]*Group
+	exprsByObj   map[types.Object]boundExpr
+	walking      map[*ast.FuncDecl]bool // recursion guard
+	walked       map[*ast.FuncDecl]bool
+	diagnostics  []Diagnostic
+	reported     map[*types.Func]bool // marshalers already reported
 }
 
-func NewEndpointsVisitor(pkgs []*packages.Package) *EndpointsVisitor {
+// boundExpr is an expression along with the package holding its type
+// information, which is needed as soon as bindings cross package boundaries.
+type boundExpr struct {
+	expr ast.Expr
+	pkg  *packages.Package
+}
+
+// NewEndpointsVisitor prepares the discovery of the endpoints registered by the
+// entrypoints, following calls into any of the loaded packages.
+func NewEndpointsVisitor(pkgs []*packages.Package, entrypoints []*packages.Package) *EndpointsVisitor {
 	v := &EndpointsVisitor{
 		root:         &Group{},
 		schemas:      NewSchemaRegistry(),
-		entrypoint:   nil,
+		entrypoints:  entrypoints,
 		pkgsByID:     map[string]*packages.Package{},
 		groupsByExpr: map[ast.Expr]*Group{},
-		exprsByIdent: map[ast.Object]ast.Expr{},
-	}
-
-	// entrypoint is always the last package
-	if l := len(pkgs); l > 0 {
-		v.entrypoint = pkgs[l-1]
+		groupsByObj:  map[types.Object]*Group{},
+		exprsByObj:   map[types.Object]boundExpr{},
+		walking:      map[*ast.FuncDecl]bool{},
+		walked:       map[*ast.FuncDecl]bool{},
+		reported:     map[*types.Func]bool{},
 	}
+	v.schemas.marshaledType = v.marshaledType
+	v.schemas.enumValues = v.enumValues
 
-	// indexing packages by id
-	for _, pkg := range pkgs {
+	// indexing packages by id, dependencies included so that handlers, docs
+	// and constants declared in other modules are found too
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
 		v.pkgsByID[pkg.ID] = pkg
-	}
+	})
 
 	return v
 }
 
 func (v *EndpointsVisitor) Walk() {
-	if v.entrypoint != nil {
-		v.walk(v.entrypoint.Syntax[0], v.entrypoint)
+	for _, entrypoint := range v.entrypoints {
+		v.walkEntrypoint(entrypoint)
+	}
+}
+
+func (v *EndpointsVisitor) walkEntrypoint(entrypoint *packages.Package) {
+	// Start the same way the program does: package-level declarations, init
+	// functions, then main. Functions are followed from there, whatever the
+	// file or package they are declared in.
+	for _, file := range entrypoint.Syntax {
+		for _, decl := range file.Decls {
+			if gendecl, ok := decl.(*ast.GenDecl); ok {
+				v.walk(gendecl, entrypoint)
+			}
+		}
+	}
+	for _, name := range []string{"init", "main"} {
+		for _, file := range entrypoint.Syntax {
+			for _, decl := range file.Decls {
+				if fdecl, ok := decl.(*ast.FuncDecl); ok && fdecl.Recv == nil && fdecl.Name.Name == name {
+					v.walkFuncDecl(fdecl, entrypoint)
+				}
+			}
+		}
+	}
+
+	// A library has no main to start from: walk all of its functions. The
+	// ones main does not reach are dead code, left out.
+	if entrypoint.Name == "main" {
+		return
+	}
+	for _, file := range entrypoint.Syntax {
+		for _, decl := range file.Decls {
+			if fdecl, ok := decl.(*ast.FuncDecl); ok && !v.walked[fdecl] {
+				v.walkFuncDecl(fdecl, entrypoint)
+			}
+		}
 	}
 }
 
+func (v *EndpointsVisitor) walkFuncDecl(fdecl *ast.FuncDecl, pkg *packages.Package) {
+	v.walked[fdecl] = true
+	v.walking[fdecl] = true
+	v.walk(fdecl, pkg)
+	delete(v.walking, fdecl)
+}
+
 func (v *EndpointsVisitor) Endpoints() []*Endpoint {
-	return v.root.all()
+	// NoRoute/NoMethod handlers are combined with the engine middlewares
+	// whenever either changes, so only their final state matters.
+	for _, g := range v.root.groups {
+		if len(g.noRoute) == 0 && len(g.noMethod) == 0 {
+			continue
+		}
+
+		var fallbacks []openapi3.Responses
+		for _, handlers := range [][]boundExpr{g.noRoute, g.noMethod} {
+			if len(handlers) > 0 {
+				info := v.inferHandlers("", append(append([]boundExpr{}, g.middlewares...), handlers...))
+				fallbacks = append(fallbacks, info.responses)
+			}
+		}
+
+		if def := defaultResponse(fallbacks...); def != nil {
+			g.each(func(e *Endpoint) {
+				if existing := e.Responses.Default(); existing == nil || isPlaceholder(existing) {
+					e.Responses["default"] = def
+				}
+			})
+		}
+	}
+
+	endpoints := v.root.all()
+	for _, e := range endpoints {
+		var conflicts []string
+		e.Params, conflicts = mergeParams(refinePathParams(e.Params, e.uriParams))
+		for _, conflict := range conflicts {
+			v.diagnostics = append(v.diagnostics, Diagnostic{Message: fmt.Sprintf("%s %s: %s", e.Method, e.Path, conflict)})
+		}
+	}
+
+	return endpoints
+}
+
+// foldPath folds the path a route or group is registered with by method,
+// reporting it when it cannot be known.
+func (v *EndpointsVisitor) foldPath(expr ast.Expr, pkg *packages.Package, method string) (string, bool) {
+	value, ok := v.foldConstant(expr, pkg, map[types.Object]bool{})
+	if !ok || value.Kind() != constant.String {
+		v.diagnostics = append(v.diagnostics, Diagnostic{
+			Pos:     pkg.Fset.Position(expr.Pos()),
+			Message: fmt.Sprintf("cannot resolve the path passed to %s, the routes it registers are left out", method),
+		})
+		return "", false
+	}
+	return constant.StringVal(value), true
+}
+
+// Diagnostics returns what could not be documented faithfully so far.
+func (v *EndpointsVisitor) Diagnostics() []Diagnostic {
+	return v.diagnostics
 }
 
 func (v *EndpointsVisitor) walk(node ast.Node, pkg *packages.Package) {
 	ast.Inspect(node, func(n ast.Node) bool {
 		// Gather and store assignements and var declarations as we find them to
 		// make it possible to resolve identifiers chains
-		{
-			if assignstmt, ok := n.(*ast.AssignStmt); ok {
-				for i, lhs := range assignstmt.Lhs {
-					if i >= len(assignstmt.Rhs) {
-						break
-					}
-					if ident, ok := lhs.(*ast.Ident); ok && ident.Obj != nil {
-						v.exprsByIdent[*ident.Obj] = assignstmt.Rhs[i]
-					}
-				}
-				return true
-			}
+		if v.recordBinding(n, pkg) {
+			return true
+		}
 
-			if valuespec, ok := n.(*ast.ValueSpec); ok {
-				for i, ident := range valuespec.Names {
-					if i >= len(valuespec.Values) {
-						break
-					}
-					v.exprsByIdent[*ident.Obj] = valuespec.Values[i]
-				}
-				return true
-			}
+		// Unroll loops over statically known collections, e.g. table-driven
+		// route registrations.
+		if rangestmt, ok := n.(*ast.RangeStmt); ok {
+			return !v.unrollRange(rangestmt, pkg)
 		}
 
 		callexpr, ok := n.(*ast.CallExpr)
@@ -99,16 +200,34 @@ func (v *EndpointsVisitor) walk(node ast.Node, pkg *packages.Package) {
 				}
 			}
 
+			// Methods on server structs holding an engine/routergroup are
+			// followed as well, even when it is not passed explicitly.
+			if selectorexpr, ok := callexpr.Fun.(*ast.SelectorExpr); ok && !follow {
+				if sel := pkg.TypesInfo.Selections[selectorexpr]; sel != nil && sel.Kind() == types.MethodVal {
+					follow = hasGinField(sel.Recv())
+				}
+			}
+
+			// So are factories returning an engine/routergroup, e.g.
+			// r := router.New(), whatever package they are declared in.
+			index, kind := ginResult(pkg.TypesInfo.Types[callexpr].Type)
+			if fn := calledFunc(callexpr, pkg); fn == nil || fn.Pkg() == nil || isGinPkg(fn.Pkg().Path()) {
+				kind = Unknown // gin.New(), r.Group(...) are modeled below
+			}
+			follow = follow || kind != Unknown
+
 			if follow {
-				if fdecl, fpkg := v.resolveFunctionDeclaration(callexpr, pkg); fdecl != nil {
-					i := 0
-					for _, param := range fdecl.Type.Params.List {
-						for _, name := range param.Names {
-							v.exprsByIdent[*name.Obj] = callexpr.Args[i]
-							i++
-						}
+				// arguments are evaluated first, e.g. mount(router.Group("/a"))
+				for _, arg := range callexpr.Args {
+					v.walk(arg, pkg)
+				}
+
+				if fdecl, fpkg := v.resolveFunctionDeclaration(callexpr, pkg); fdecl != nil && !v.walking[fdecl] && !isGinPkg(fpkg.PkgPath) {
+					v.bindParams(fdecl, fpkg, callexpr, pkg)
+					v.walkFuncDecl(fdecl, fpkg)
+					if kind != Unknown {
+						v.bindResult(callexpr, fdecl, fpkg, index, kind)
 					}
-					v.walk(fdecl, fpkg)
 				}
 			}
 		}
@@ -130,14 +249,8 @@ func (v *EndpointsVisitor) walk(node ast.Node, pkg *packages.Package) {
 				return false
 			}
 
-			if kind := resolveGinKind(pkg.TypesInfo.Types[x].Type); kind != Unknown {
-				var parent *Group
-				if kind == Engine {
-					parent = v.root
-				} else if kind == RouterGroup {
-					parent = v.groupsByExpr[v.resolveExpr(x)]
-				}
-
+			if kind := resolveGinKind(pkg.TypesInfo.TypeOf(x)); kind != Unknown {
+				parent := v.resolveGroup(x, kind, pkg)
 				if parent == nil {
 					return false
 				}
@@ -145,31 +258,27 @@ func (v *EndpointsVisitor) walk(node ast.Node, pkg *packages.Package) {
 				switch selector.Sel.Name {
 				case "Group":
 					if len(callexpr.Args) >= 1 {
-						if arg0, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
+						if arg0, ok := v.foldPath(callexpr.Args[0], pkg, selector.Sel.Name); ok {
 							path, pathParams := inferPath(arg0)
-							g := &Group{Path: path, Params: pathParams}
+							g := &Group{
+								Path:        path,
+								Params:      pathParams,
+								middlewares: parent.combineHandlers(v.handlerArgs(callexpr, 1, pkg)),
+							}
 							v.groupsByExpr[callexpr] = g
 							parent.groups = append(parent.groups, g)
 						}
 					}
 
+				case "Use":
+					parent.middlewares = parent.combineHandlers(v.handlerArgs(callexpr, 0, pkg))
+
 				case "Handle":
 					if len(callexpr.Args) > 2 {
 						if m, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
-							if arg1, ok := v.foldStringConstant(callexpr.Args[1], pkg); ok {
-								path, pathParams := inferPath(arg1)
-								reqBody, handlerParams, res := v.inferHandler(callexpr.Args[len(callexpr.Args)-1], pkg)
-
-								endpoint := &Endpoint{
-									Method:      m,
-									Path:        path,
-									RequestBody: reqBody,
-									Responses:   res,
-								}
-								endpoint.Params = append(endpoint.Params, pathParams...)
-								endpoint.Params = append(endpoint.Params, handlerParams...)
-
-								parent.endpoints = append(parent.endpoints, endpoint)
+							if arg1, ok := v.foldPath(callexpr.Args[1], pkg, selector.Sel.Name); ok {
+								handlers := parent.combineHandlers(v.handlerArgs(callexpr, 2, pkg))
+								parent.endpoints = append(parent.endpoints, v.newEndpoint(m, arg1, handlers))
 							}
 						}
 					}
@@ -177,22 +286,58 @@ func (v *EndpointsVisitor) walk(node ast.Node, pkg *packages.Package) {
 				case "POST", "GET", "HEAD", "PUT", "PATCH", "DELETE", "OPTIONS":
 					if len(callexpr.Args) >= 2 {
 						m := selector.Sel.Name
-						if arg0, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
-							path, pathParams := inferPath(arg0)
-							reqBody, handlerParams, res := v.inferHandler(callexpr.Args[len(callexpr.Args)-1], pkg)
+						if arg0, ok := v.foldPath(callexpr.Args[0], pkg, selector.Sel.Name); ok {
+							handlers := parent.combineHandlers(v.handlerArgs(callexpr, 1, pkg))
+							parent.endpoints = append(parent.endpoints, v.newEndpoint(m, arg0, handlers))
+						}
+					}
 
-							endpoint := &Endpoint{
-								Method:      m,
-								Path:        path,
-								RequestBody: reqBody,
-								Responses:   res,
+				case "Any":
+					if len(callexpr.Args) >= 2 {
+						if arg0, ok := v.foldPath(callexpr.Args[0], pkg, selector.Sel.Name); ok {
+							handlers := parent.combineHandlers(v.handlerArgs(callexpr, 1, pkg))
+							for _, m := range anyMethods {
+								parent.endpoints = append(parent.endpoints, v.newEndpoint(m, arg0, handlers))
+							}
+						}
+					}
+
+				case "Match":
+					if len(callexpr.Args) >= 3 {
+						if arg1, ok := v.foldPath(callexpr.Args[1], pkg, selector.Sel.Name); ok {
+							handlers := parent.combineHandlers(v.handlerArgs(callexpr, 2, pkg))
+							methods, _ := v.foldStringSlice(callexpr.Args[0], pkg, map[types.Object]bool{})
+							for _, m := range methods {
+								parent.endpoints = append(parent.endpoints, v.newEndpoint(m, arg1, handlers))
 							}
-							endpoint.Params = append(endpoint.Params, pathParams...)
-							endpoint.Params = append(endpoint.Params, handlerParams...)
+						}
+					}
 
-							parent.endpoints = append(parent.endpoints, endpoint)
+				case "StaticFile", "Static", "StaticFS":
+					if len(callexpr.Args) >= 2 {
+						if arg0, ok := v.foldPath(callexpr.Args[0], pkg, selector.Sel.Name); ok {
+							ginPath := arg0
+							if selector.Sel.Name != "StaticFile" {
+								ginPath = path.Join(arg0, "/*filepath")
+							}
+							handlers := parent.combineHandlers(nil)
+							for _, m := range []string{http.MethodGet, http.MethodHead} {
+								endpoint := v.newEndpoint(m, ginPath, handlers)
+								addStaticResponses(endpoint, selector.Sel.Name != "StaticFile")
+								parent.endpoints = append(parent.endpoints, endpoint)
+							}
 						}
 					}
+
+				case "NoRoute":
+					if kind == Engine {
+						parent.noRoute = toBoundExprs(callexpr.Args, pkg)
+					}
+
+				case "NoMethod":
+					if kind == Engine {
+						parent.noMethod = toBoundExprs(callexpr.Args, pkg)
+					}
 				}
 
 				return false
@@ -203,357 +348,1335 @@ func (v *EndpointsVisitor) walk(node ast.Node, pkg *packages.Package) {
 	})
 }
 
-func (v *EndpointsVisitor) foldIntConstant(expr ast.Expr, pkg *packages.Package) (int, bool) {
-	if expr == nil {
-		return 0, false
+// resolveGroup returns the group a gin engine or router group expression
+// stands for. Each distinct engine gets its own group under the root one, so
+// that middlewares registered on one do not leak onto the others.
+func (v *EndpointsVisitor) resolveGroup(x *ast.Ident, kind GinKind, pkg *packages.Package) *Group {
+	resolved, rpkg := v.resolveExpr(x, pkg)
+	return v.groupFor(resolved, kind, rpkg)
+}
+
+// groupFor returns the group a resolved expression stands for, creating it
+// when it is a new engine.
+func (v *EndpointsVisitor) groupFor(resolved ast.Expr, kind GinKind, rpkg *packages.Package) *Group {
+	// unresolved identifiers (e.g. struct fields) are keyed by their object so
+	// that every use of them maps to the same group
+	var obj types.Object
+	if ident, ok := resolved.(*ast.Ident); ok {
+		obj = rpkg.TypesInfo.ObjectOf(ident)
 	}
 
-	ty, ok := pkg.TypesInfo.Types[expr]
-	if !ok {
-		return 0, false
+	if obj != nil {
+		if g, ok := v.groupsByObj[obj]; ok {
+			return g
+		}
+	} else if g, ok := v.groupsByExpr[resolved]; ok {
+		return g
 	}
 
-	if ty.Value == nil {
-		return 0, false
+	if kind != Engine {
+		return nil
 	}
 
-	folded, ok := constant.Int64Val(ty.Value)
-	if !ok {
-		return 0, false
+	g := &Group{}
+	if obj != nil {
+		v.groupsByObj[obj] = g
+	} else {
+		v.groupsByExpr[resolved] = g
 	}
+	v.root.groups = append(v.root.groups, g)
 
-	return int(folded), true
+	return g
 }
 
-func (v *EndpointsVisitor) foldStringConstant(expr ast.Expr, pkg *packages.Package) (string, bool) {
-	if expr == nil {
-		return "", false
+// bindResult maps a call to a factory to the group the function returns, so
+// that the routes registered on its result land in that group.
+func (v *EndpointsVisitor) bindResult(callexpr *ast.CallExpr, fdecl *ast.FuncDecl, fpkg *packages.Package, index int, kind GinKind) {
+	if fdecl.Body == nil {
+		return
 	}
 
-	ty, ok := pkg.TypesInfo.Types[expr]
-	if !ok {
-		return "", false
-	}
+	ast.Inspect(fdecl.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
 
-	if ty.Value == nil {
-		return "", false
-	}
+		ret, ok := n.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) <= index {
+			return true
+		}
 
-	if basic, ok := ty.Type.(*types.Basic); !ok || basic.Kind() != types.String {
-		return "", false
-	}
+		result := unparen(ret.Results[index])
+		if fpkg.TypesInfo.Types[result].IsNil() {
+			return false
+		}
 
-	folded := constant.StringVal(ty.Value)
-	if len(folded) == 0 {
-		return "", false
+		var g *Group
+		switch e := result.(type) {
+		case *ast.Ident:
+			g = v.resolveGroup(e, kind, fpkg)
+		case *ast.SelectorExpr:
+			g = v.resolveGroup(e.Sel, kind, fpkg)
+		default:
+			g = v.groupFor(e, kind, fpkg)
+		}
+		if g != nil {
+			v.groupsByExpr[callexpr] = g
+		}
+		return false
+	})
+}
+
+// ginResult returns the index and kind of the engine/routergroup among the
+// results of a call of type ty, if any.
+func ginResult(ty types.Type) (int, GinKind) {
+	if tuple, ok := ty.(*types.Tuple); ok {
+		for i := 0; i < tuple.Len(); i++ {
+			if kind := resolveGinKind(tuple.At(i).Type()); kind != Unknown {
+				return i, kind
+			}
+		}
+		return 0, Unknown
 	}
+	return 0, resolveGinKind(ty)
+}
 
-	return folded, true
-}
-
-func (v *EndpointsVisitor) inferHandler(expr ast.Expr, pkg *packages.Package) (*openapi3.RequestBodyRef, openapi3.Parameters, openapi3.Responses) {
-	var requestBody *openapi3.RequestBodyRef
-	var params openapi3.Parameters
-	responses := openapi3.Responses{}
-
-	if lit, ok := expr.(*ast.FuncLit); ok {
-		ast.Inspect(lit, func(n ast.Node) bool {
-			if callexpr, ok := n.(*ast.CallExpr); ok {
-				if selectorexpr, ok := callexpr.Fun.(*ast.SelectorExpr); ok {
-					if isGinContext(pkg.TypesInfo.Types[selectorexpr.X].Type) {
-						switch selectorexpr.Sel.Name {
-						case "Query":
-							if len(callexpr.Args) > 0 {
-								if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
-									params = append(params, &openapi3.ParameterRef{
-										Value: &openapi3.Parameter{
-											In:   openapi3.ParameterInQuery,
-											Name: name,
-											Schema: &openapi3.SchemaRef{
-												Value: &openapi3.Schema{
-													Type: openapi3.TypeString,
-												},
-											},
-										},
-									})
-								}
-							}
+// isGinPkg reports whether path is gin or one of its packages, which are
+// modeled rather than walked.
+func isGinPkg(path string) bool {
+	return path == "github.com/gin-gonic/gin" || strings.HasPrefix(path, "github.com/gin-gonic/gin/")
+}
 
-						case "DefaultQuery":
-							if len(callexpr.Args) > 1 {
-								if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
-									if defaultValue, ok := v.foldStringConstant(callexpr.Args[1], pkg); ok {
-										params = append(params, &openapi3.ParameterRef{
-											Value: &openapi3.Parameter{
-												In:   openapi3.ParameterInQuery,
-												Name: name,
-												Schema: &openapi3.SchemaRef{
-													Value: &openapi3.Schema{
-														Type:    openapi3.TypeString,
-														Default: defaultValue,
-													},
-												},
-											},
-										})
-									}
-								}
-							}
+// anyMethods are the methods registered by RouterGroup.Any.
+var anyMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodTrace,
+}
 
-						case "ShouldBindQuery", "BindQuery":
-							if len(callexpr.Args) > 0 {
-								arg0 := pkg.TypesInfo.Types[callexpr.Args[0]].Type
-								p := paramsFromStructFields(arg0, "form", openapi3.ParameterInQuery)
-								params = append(params, p...)
-							}
+func toBoundExprs(exprs []ast.Expr, pkg *packages.Package) []boundExpr {
+	out := make([]boundExpr, 0, len(exprs))
+	for _, expr := range exprs {
+		out = append(out, boundExpr{expr, pkg})
+	}
+	return out
+}
 
-						case "GetHeader":
-							if len(callexpr.Args) > 0 {
-								if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
-									params = append(params, &openapi3.ParameterRef{
-										Value: &openapi3.Parameter{
-											In:   openapi3.ParameterInHeader,
-											Name: name,
-											Schema: &openapi3.SchemaRef{
-												Value: &openapi3.Schema{
-													Type: openapi3.TypeString,
-												},
-											},
-										},
-									})
-								}
-							}
+// handlerArgs returns the arguments of callexpr from the i-th on, expanding the
+// elements of a composite literal spread with ..., e.g. in r.Use(mws...).
+func (v *EndpointsVisitor) handlerArgs(callexpr *ast.CallExpr, i int, pkg *packages.Package) []boundExpr {
+	if i >= len(callexpr.Args) {
+		return nil
+	}
+	args := toBoundExprs(callexpr.Args[i:], pkg)
+	if !callexpr.Ellipsis.IsValid() {
+		return args
+	}
 
-						case "ShouldBindHeader", "BindHeader":
-							if len(callexpr.Args) > 0 {
-								arg0 := pkg.TypesInfo.Types[callexpr.Args[0]].Type
-								p := paramsFromStructFields(arg0, "header", openapi3.ParameterInHeader)
-								params = append(params, p...)
-							}
+	spread, spkg := callexpr.Args[len(callexpr.Args)-1], pkg
+	if ident, ok := spread.(*ast.Ident); ok {
+		spread, spkg = v.resolveExpr(ident, pkg)
+	}
+	lit, ok := unparen(spread).(*ast.CompositeLit)
+	if !ok {
+		return args
+	}
 
-						case "ShouldBindJSON", "BindJSON":
-							if len(callexpr.Args) > 0 {
-								arg0 := pkg.TypesInfo.Types[callexpr.Args[0]].Type
-								requestSchema := v.schemas.ToSchemaRef(arg0, "json")
-								if requestBody == nil {
-									requestBody = &openapi3.RequestBodyRef{
-										Value: &openapi3.RequestBody{
-											Content: map[string]*openapi3.MediaType{
-												"application/json": {
-													Schema: &openapi3.SchemaRef{
-														Value: &openapi3.Schema{
-															OneOf: []*openapi3.SchemaRef{requestSchema},
-														},
-													},
-												},
-											},
-										},
-									}
-								} else {
-									v := requestBody.Value.Content.Get("application/json").Schema.Value
-									v.OneOf = append(v.OneOf, requestSchema)
-								}
-							}
+	args = args[:len(args)-1]
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+		}
+		args = append(args, boundExpr{elt, spkg})
+	}
+	return args
+}
 
-						case "AbortWithError", "AbortWithStatus":
-							if len(callexpr.Args) > 0 {
-								if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
-									d := "description"
-									responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
-										Value: &openapi3.Response{
-											Description: &d,
-										},
-									}
-								}
-							}
+// addStaticResponses documents the files served by the static handlers of gin,
+// which are not part of the analyzed code.
+func addStaticResponses(endpoint *Endpoint, folder bool) {
+	if isPlaceholder(endpoint.Responses.Default()) {
+		delete(endpoint.Responses, "default")
+	}
 
-						case "AbortWithStatusJSON", "AsciiJSON", "IndentedJSON", "JSON", "PureJSON", "SecureJSON":
-							if len(callexpr.Args) > 1 {
-								if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
-									arg1 := pkg.TypesInfo.Types[callexpr.Args[1]].Type
-									d := "description"
-									responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
-										Value: &openapi3.Response{
-											Description: &d,
-											Content: openapi3.Content{
-												"application/json": &openapi3.MediaType{
-													Schema: v.schemas.ToSchemaRef(arg1, "json"),
-												},
-											},
-										},
-									}
-								}
-							}
+	d := "description"
+	endpoint.Responses["200"] = &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Description: &d,
+			Content: openapi3.Content{
+				"application/octet-stream": &openapi3.MediaType{
+					Schema: &openapi3.SchemaRef{Value: openapi3.NewStringSchema().WithFormat("binary")},
+				},
+			},
+		},
+	}
 
-						case "Data":
-							if len(callexpr.Args) > 1 {
-								if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
-									if contentType, ok := v.foldStringConstant(callexpr.Args[1], pkg); ok {
-										d := "description"
-										responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
-											Value: &openapi3.Response{
-												Description: &d,
-												Content: openapi3.Content{
-													contentType: &openapi3.MediaType{},
-												},
-											},
-										}
-									}
-								}
-							}
+	if folder {
+		nf := "description"
+		endpoint.Responses["404"] = &openapi3.ResponseRef{
+			Value: &openapi3.Response{
+				Description: &nf,
+			},
+		}
+	}
+}
 
-						case "DataFromReader":
-							if len(callexpr.Args) > 2 {
-								if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
-									if contentType, ok := v.foldStringConstant(callexpr.Args[2], pkg); ok {
-										d := "description"
-										responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
-											Value: &openapi3.Response{
-												Description: &d,
-												Content: openapi3.Content{
-													contentType: &openapi3.MediaType{},
-												},
-											},
-										}
-									}
-								}
-							}
+// defaultResponse merges the responses of NoRoute/NoMethod handlers into a
+// single default response, or returns nil if they do not document anything.
+func defaultResponse(fallbacks ...openapi3.Responses) *openapi3.ResponseRef {
+	d := "description"
+	out := &openapi3.Response{Description: &d}
+
+	var found bool
+	for _, responses := range fallbacks {
+		statuses := make([]string, 0, len(responses))
+		for status := range responses {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
 
-						case "HTML", "Render":
-							if len(callexpr.Args) > 0 {
-								if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
-									d := "description"
-									responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
-										Value: &openapi3.Response{
-											Description: &d,
-											Content: openapi3.Content{
-												"text/html": &openapi3.MediaType{},
-											},
-										},
-									}
-								}
-							}
+		for _, status := range statuses {
+			res := responses[status]
+			if res == nil || res.Value == nil || isPlaceholder(res) {
+				continue
+			}
+			found = true
+			for contentType, media := range res.Value.Content {
+				if out.Content == nil {
+					out.Content = openapi3.Content{}
+				}
+				if _, ok := out.Content[contentType]; !ok {
+					out.Content[contentType] = media
+				}
+			}
+		}
+	}
 
-						case "JSONP":
-							if len(callexpr.Args) > 1 {
-								if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
-									arg1 := pkg.TypesInfo.Types[callexpr.Args[1]].Type
-									d := "description"
-									responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
-										Value: &openapi3.Response{
-											Description: &d,
-											Content: openapi3.Content{
-												"application/javascript": &openapi3.MediaType{
-													Schema: v.schemas.ToSchemaRef(arg1, "json"),
-												},
-											},
-										},
-									}
-								}
-							}
+	if !found {
+		return nil
+	}
 
-						case "Redirect", "Status", "String":
-							if len(callexpr.Args) > 0 {
-								if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
-									d := "description"
-									responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
-										Value: &openapi3.Response{
-											Description: &d,
-										},
-									}
-								}
-							}
+	return &openapi3.ResponseRef{Value: out}
+}
 
-						case "XML":
-							if len(callexpr.Args) > 1 {
-								if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
-									arg1 := pkg.TypesInfo.Types[callexpr.Args[1]].Type
-									d := "description"
-									responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
-										Value: &openapi3.Response{
-											Description: &d,
-											Content: openapi3.Content{
-												"text/xml": &openapi3.MediaType{
-													Schema: v.schemas.ToSchemaRef(arg1, "xml"),
-												},
-											},
-										},
-									}
-								}
-							}
+// isPlaceholder reports whether res is the empty default response added when
+// nothing could be inferred from the handlers.
+func isPlaceholder(res *openapi3.ResponseRef) bool {
+	return res != nil && res.Ref == "" && res.Value != nil && res.Value.Description != nil && *res.Value.Description == "" && len(res.Value.Content) == 0
+}
 
-						case "YAML":
-							if len(callexpr.Args) > 1 {
-								if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
-									arg1 := pkg.TypesInfo.Types[callexpr.Args[1]].Type
-									d := "description"
-									responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
-										Value: &openapi3.Response{
-											Description: &d,
-											Content: openapi3.Content{
-												"text/yaml": &openapi3.MediaType{
-													Schema: v.schemas.ToSchemaRef(arg1, "yaml"),
-												},
-											},
-										},
-									}
-								}
-							}
+// newEndpoint infers an endpoint from its method, gin path and handlers chain.
+func (v *EndpointsVisitor) newEndpoint(method string, ginPath string, handlers []boundExpr) *Endpoint {
+	path, pathParams := inferPath(ginPath)
+	info := v.inferHandlers(method, handlers)
+
+	endpoint := &Endpoint{
+		Method:      method,
+		Path:        path,
+		RequestBody: info.requestBody,
+		Responses:   info.responses,
+		uriParams:   info.uriParams,
+	}
+	endpoint.Params = append(endpoint.Params, pathParams...)
+	endpoint.Params = append(endpoint.Params, info.params...)
 
-						}
-					}
-				}
-				return false
-			}
+	return endpoint
+}
 
-			return true
-		})
+// handlerInfo accumulates what could be inferred from a chain of gin handlers.
+type handlerInfo struct {
+	method      string
+	requestBody *openapi3.RequestBodyRef
+	params      openapi3.Parameters
+	responses   openapi3.Responses
+	uriParams   openapi3.Parameters    // refinements of the path parameters
+	visiting    map[*ast.FuncDecl]bool // helpers being inspected
+
+	// form request bodies are built from every field read
+	form          *openapi3.Schema
+	formEncodings map[string]*openapi3.Encoding
+	multipart     bool
+
+	conversions []conversion // parameters parsed into other types
+	rawBody     bool         // whether the body is read as raw bytes
+}
+
+// inferHandlers infers the request body, parameters and responses of a route
+// from its whole chain of handlers (middlewares first). The method is empty
+// when the handlers may serve any route.
+func (v *EndpointsVisitor) inferHandlers(method string, handlers []boundExpr) *handlerInfo {
+	info := &handlerInfo{
+		method:    method,
+		responses: openapi3.Responses{},
+		visiting:  map[*ast.FuncDecl]bool{},
+	}
+
+	for _, h := range handlers {
+		for _, handler := range v.resolveHandler(h.expr, h.pkg, map[ast.Node]bool{}) {
+			v.inspectHandler(handler.body, handler.pkg, info)
+		}
 	}
 
 	// for each content, flatten if there is only one possible type
-	if requestBody != nil && requestBody.Value != nil && requestBody.Value.Content != nil {
-		for _, content := range requestBody.Value.Content {
+	if info.requestBody != nil && info.requestBody.Value != nil && info.requestBody.Value.Content != nil {
+		for _, content := range info.requestBody.Value.Content {
 			if content != nil && content.Schema != nil && content.Schema.Value != nil && len(content.Schema.Value.OneOf) == 1 {
 				content.Schema = content.Schema.Value.OneOf[0]
 			}
 		}
 	}
 
-	if len(responses) == 0 {
-		responses = openapi3.NewResponses()
+	info.applyConversions()
+
+	// bodies read as bytes and never decoded are documented as binary
+	if info.rawBody && info.requestBody == nil && info.form == nil && !info.multipart {
+		info.requestBody = &openapi3.RequestBodyRef{
+			Value: openapi3.NewRequestBody().WithContent(openapi3.NewContentWithSchema(openapi3.NewStringSchema().WithFormat("binary"), []string{"application/octet-stream"})),
+		}
+	}
+
+	if info.form != nil || info.multipart {
+		info.addFormBodies()
+	}
+
+	if len(info.responses) == 0 {
+		info.responses = openapi3.NewResponses()
 	}
 
-	return requestBody, params, responses
+	return info
 }
 
-func (v *EndpointsVisitor) resolveFunctionDeclaration(callexpr *ast.CallExpr, pkg *packages.Package) (*ast.FuncDecl, *packages.Package) {
-	if selectorexpr, ok := callexpr.Fun.(*ast.SelectorExpr); ok {
-		if ident, ok := selectorexpr.X.(*ast.Ident); ok {
-			if fpkgName, ok := pkg.TypesInfo.Uses[ident].(*types.PkgName); ok && fpkgName != nil {
-				if fpkg := v.pkgsByID[fpkgName.Imported().Path()]; fpkg != nil {
-					for _, decl := range fpkg.Syntax[0].Decls {
-						if fdecl, ok := decl.(*ast.FuncDecl); ok {
-							if fdecl.Name.IsExported() && fdecl.Name.Name == selectorexpr.Sel.Name {
-								return fdecl, fpkg
-							}
-						}
-					}
+// addRequestBody adds schema to the possible request bodies of contentType.
+func (info *handlerInfo) addRequestBody(contentType string, schema *openapi3.SchemaRef) {
+	if info.requestBody == nil {
+		info.requestBody = &openapi3.RequestBodyRef{
+			Value: &openapi3.RequestBody{
+				Content: openapi3.Content{},
+			},
+		}
+	}
+
+	media := info.requestBody.Value.Content.Get(contentType)
+	if media == nil {
+		media = &openapi3.MediaType{
+			Schema: &openapi3.SchemaRef{
+				Value: &openapi3.Schema{},
+			},
+		}
+		info.requestBody.Value.Content[contentType] = media
+	}
+
+	media.Schema.Value.OneOf = append(media.Schema.Value.OneOf, schema)
+}
+
+// addFormField adds a field to the form request body, whatever the way it is
+// read (gin reads url-encoded and multipart forms the same way).
+func (info *handlerInfo) addFormField(name string, schema *openapi3.SchemaRef, required bool) {
+	if info.form == nil {
+		info.form = openapi3.NewObjectSchema()
+	}
+
+	// the first read wins, unless it was a plain PostForm and the field is
+	// known more precisely afterwards
+	if prev, ok := info.form.Properties[name]; !ok || isPlainString(prev) {
+		info.form.Properties[name] = schema
+	}
+
+	if required {
+		for _, r := range info.form.Required {
+			if r == name {
+				return
+			}
+		}
+		info.form.Required = append(info.form.Required, name)
+	}
+
+	if isBinary(schema) {
+		info.multipart = true
+	}
+}
+
+func (info *handlerInfo) formEncoding(name string) *openapi3.Encoding {
+	if info.formEncodings == nil {
+		info.formEncodings = map[string]*openapi3.Encoding{}
+	}
+	if _, ok := info.formEncodings[name]; !ok {
+		info.formEncodings[name] = openapi3.NewEncoding()
+	}
+	return info.formEncodings[name]
+}
+
+// addFormBodies documents the form fields as multipart and, unless files are
+// uploaded, url-encoded request bodies.
+func (info *handlerInfo) addFormBodies() {
+	if info.form == nil {
+		info.form = openapi3.NewObjectSchema()
+	}
+
+	if info.requestBody == nil {
+		info.requestBody = &openapi3.RequestBodyRef{
+			Value: &openapi3.RequestBody{
+				Content: openapi3.Content{},
+			},
+		}
+	}
+
+	if !info.multipart {
+		info.requestBody.Value.Content["application/x-www-form-urlencoded"] = &openapi3.MediaType{
+			Schema:   &openapi3.SchemaRef{Value: info.form},
+			Encoding: info.formEncodings,
+		}
+	}
+
+	info.requestBody.Value.Content["multipart/form-data"] = &openapi3.MediaType{
+		Schema: &openapi3.SchemaRef{Value: info.form},
+	}
+}
+
+// bodyBindings are the gin bindings reading the request body, along with the
+// content type they are meant for and the struct tag they read.
+var bodyBindings = map[string]struct {
+	contentType string
+	tag         string
+}{
+	"JSON":     {"application/json", "json"},
+	"XML":      {"application/xml", "xml"},
+	"YAML":     {"application/x-yaml", "yaml"},
+	"TOML":     {"application/toml", "toml"},
+	"ProtoBuf": {"application/x-protobuf", "json"},
+	"MsgPack":  {"application/x-msgpack", "json"},
+}
+
+// defaultBindings are the bindings ShouldBind and Bind pick from the content
+// type of requests with a body, as binding.Default does. Form documents both
+// the url-encoded and the multipart (FormMultipart) bodies, and ProtoBuf only
+// applies to protobuf messages.
+var defaultBindings = []string{"JSON", "XML", "ProtoBuf", "MsgPack", "YAML", "Form"}
+
+// bindWith documents a value of type ty bound with the named gin binding.
+func (v *EndpointsVisitor) bindWith(info *handlerInfo, binding string, ty types.Type) {
+	if ty == nil {
+		return
+	}
+
+	if b, ok := bodyBindings[binding]; ok {
+		info.addRequestBody(b.contentType, v.schemas.ToSchemaRef(ty, b.tag))
+		return
+	}
+
+	switch binding {
+	case "Form", "FormPost", "FormMultipart":
+		if binding == "Form" && info.method == http.MethodGet {
+			info.params = append(info.params, v.typedParamsFromStructFields(ty, "form", openapi3.ParameterInQuery)...)
+			return
+		}
+		v.addFormFieldsFromStruct(info, ty)
+		if binding == "FormMultipart" {
+			info.multipart = true
+		}
+
+	case "Query":
+		info.params = append(info.params, v.typedParamsFromStructFields(ty, "form", openapi3.ParameterInQuery)...)
+
+	case "Header":
+		info.params = append(info.params, v.typedParamsFromStructFields(ty, "header", openapi3.ParameterInHeader)...)
+
+	case "Uri":
+		info.uriParams = append(info.uriParams, v.typedParamsFromStructFields(ty, "uri", openapi3.ParameterInPath)...)
+	}
+}
+
+// addFormFieldsFromStruct adds the fields of a struct bound from a form, using
+// the form tag gin reads.
+func (v *EndpointsVisitor) addFormFieldsFromStruct(info *handlerInfo, ty types.Type) {
+	v.eachTaggedField(ty, "form", func(field *types.Var, tags *structtag.Tags, name string) {
+		schema, required := v.fieldSchema(field, tags, "form")
+		if doc := v.fieldDoc(field); doc != "" && schema != nil && schema.Ref == "" && schema.Value != nil {
+			schema.Value.Description = doc
+		}
+		info.addFormField(name, schema, required)
+	})
+}
+
+// isProtoMessage reports whether ty is a protobuf message, which is all the
+// ProtoBuf binding accepts.
+func isProtoMessage(ty types.Type) bool {
+	if ty == nil {
+		return false
+	}
+	if _, ok := ty.(*types.Pointer); !ok {
+		ty = types.NewPointer(ty)
+	}
+	return types.NewMethodSet(ty).Lookup(nil, "ProtoMessage") != nil
+}
+
+func isPlainString(schema *openapi3.SchemaRef) bool {
+	return schema.Ref == "" && schema.Value != nil && schema.Value.Type == openapi3.TypeString &&
+		schema.Value.Format == "" && schema.Value.Default == nil
+}
+
+func isBinary(schema *openapi3.SchemaRef) bool {
+	if schema == nil || schema.Value == nil {
+		return false
+	}
+	return schema.Value.Format == "binary" || isBinary(schema.Value.Items)
+}
+
+// bindingName returns the name of the gin binding (binding.JSON, binding.Form,
+// ...) expr refers to, if any.
+func bindingName(expr ast.Expr, pkg *packages.Package) string {
+	var ident *ast.Ident
+	switch e := unparen(expr).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return ""
+	}
+
+	if obj := pkg.TypesInfo.ObjectOf(ident); obj != nil && obj.Pkg() != nil && obj.Pkg().Path() == "github.com/gin-gonic/gin/binding" {
+		return obj.Name()
+	}
+	return ""
+}
+
+func (v *EndpointsVisitor) inspectHandler(body *ast.BlockStmt, pkg *packages.Package, info *handlerInfo) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		v.recordBinding(n, pkg)
+
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			for i := 0; i+1 < len(n.List); i++ {
+				if ifstmt, ok := n.List[i+1].(*ast.IfStmt); ok && ifstmt.Init == nil {
+					v.inspectParseError(n.List[i], ifstmt, pkg, info)
+				}
+			}
+		case *ast.IfStmt:
+			if n.Init != nil {
+				v.inspectParseError(n.Init, n, pkg, info)
+			}
+		}
+
+		if callexpr, ok := n.(*ast.CallExpr); ok {
+			if selectorexpr, ok := callexpr.Fun.(*ast.SelectorExpr); ok {
+				if isGinContext(pkg.TypesInfo.Types[selectorexpr.X].Type) {
+					switch selectorexpr.Sel.Name {
+					case "Query", "GetQuery":
+						if len(callexpr.Args) > 0 {
+							if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
+								info.params = append(info.params, queryParam(name, openapi3.NewStringSchema()))
+							}
+						}
+
+					case "QueryArray", "GetQueryArray":
+						if len(callexpr.Args) > 0 {
+							if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
+								schema := openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema())
+								info.params = append(info.params, queryParam(name, schema))
+							}
+						}
+
+					case "QueryMap", "GetQueryMap":
+						if len(callexpr.Args) > 0 {
+							if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
+								schema := openapi3.NewObjectSchema().WithAdditionalProperties(openapi3.NewStringSchema())
+								info.params = append(info.params, queryParam(name, schema))
+							}
+						}
+
+					case "DefaultQuery":
+						if len(callexpr.Args) > 1 {
+							if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
+								if defaultValue, ok := v.foldStringConstant(callexpr.Args[1], pkg); ok {
+									schema := openapi3.NewStringSchema()
+									schema.Default = defaultValue
+									info.params = append(info.params, queryParam(name, schema))
+								}
+							}
+						}
+
+					case "ShouldBindUri", "BindUri":
+						if len(callexpr.Args) > 0 {
+							v.bindWith(info, "Uri", v.typeOf(callexpr.Args[0], pkg))
+						}
+
+					case "ShouldBindQuery", "BindQuery":
+						if len(callexpr.Args) > 0 {
+							v.bindWith(info, "Query", v.typeOf(callexpr.Args[0], pkg))
+						}
+
+					case "GetHeader":
+						if len(callexpr.Args) > 0 {
+							if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
+								info.params = append(info.params, newParam(openapi3.ParameterInHeader, name, openapi3.NewStringSchema()))
+							}
+						}
+
+					case "Cookie":
+						if len(callexpr.Args) > 0 {
+							if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
+								info.params = append(info.params, newParam(openapi3.ParameterInCookie, name, openapi3.NewStringSchema()))
+							}
+						}
+
+					case "ShouldBindHeader", "BindHeader":
+						if len(callexpr.Args) > 0 {
+							v.bindWith(info, "Header", v.typeOf(callexpr.Args[0], pkg))
+						}
+
+					case "ShouldBindJSON", "BindJSON":
+						if len(callexpr.Args) > 0 {
+							v.bindWith(info, "JSON", v.typeOf(callexpr.Args[0], pkg))
+						}
+
+					case "ShouldBindXML", "BindXML":
+						if len(callexpr.Args) > 0 {
+							v.bindWith(info, "XML", v.typeOf(callexpr.Args[0], pkg))
+						}
+
+					case "ShouldBindYAML", "BindYAML":
+						if len(callexpr.Args) > 0 {
+							v.bindWith(info, "YAML", v.typeOf(callexpr.Args[0], pkg))
+						}
+
+					case "ShouldBindTOML", "BindTOML":
+						if len(callexpr.Args) > 0 {
+							v.bindWith(info, "TOML", v.typeOf(callexpr.Args[0], pkg))
+						}
+
+					case "ShouldBind", "Bind":
+						if len(callexpr.Args) > 0 {
+							arg0 := v.typeOf(callexpr.Args[0], pkg)
+							if info.method == http.MethodGet {
+								// gin only binds the query of GET requests
+								v.bindWith(info, "Form", arg0)
+							} else {
+								for _, name := range defaultBindings {
+									if name == "ProtoBuf" && !isProtoMessage(arg0) {
+										continue
+									}
+									v.bindWith(info, name, arg0)
+								}
+							}
+						}
+
+					case "ShouldBindWith", "MustBindWith", "BindWith", "ShouldBindBodyWith":
+						if len(callexpr.Args) > 1 {
+							v.bindWith(info, bindingName(callexpr.Args[1], pkg), v.typeOf(callexpr.Args[0], pkg))
+						}
+
+					case "PostForm", "GetPostForm":
+						if len(callexpr.Args) > 0 {
+							if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
+								info.addFormField(name, &openapi3.SchemaRef{Value: openapi3.NewStringSchema()}, false)
+							}
+						}
+
+					case "DefaultPostForm":
+						if len(callexpr.Args) > 1 {
+							if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
+								schema := openapi3.NewStringSchema()
+								if defaultValue, ok := v.foldStringConstant(callexpr.Args[1], pkg); ok {
+									schema.Default = defaultValue
+								}
+								info.addFormField(name, &openapi3.SchemaRef{Value: schema}, false)
+							}
+						}
+
+					case "PostFormArray", "GetPostFormArray":
+						if len(callexpr.Args) > 0 {
+							if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
+								schema := openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema())
+								info.addFormField(name, &openapi3.SchemaRef{Value: schema}, false)
+							}
+						}
+
+					case "PostFormMap", "GetPostFormMap":
+						if len(callexpr.Args) > 0 {
+							if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
+								schema := openapi3.NewObjectSchema().WithAdditionalProperties(openapi3.NewStringSchema())
+								info.addFormField(name, &openapi3.SchemaRef{Value: schema}, false)
+								info.formEncoding(name).Style = openapi3.SerializationDeepObject
+							}
+						}
+
+					case "FormFile":
+						if len(callexpr.Args) > 0 {
+							if name, ok := v.foldStringConstant(callexpr.Args[0], pkg); ok {
+								schema := openapi3.NewStringSchema().WithFormat("binary")
+								info.addFormField(name, &openapi3.SchemaRef{Value: schema}, false)
+							}
+						}
+						info.multipart = true
+
+					case "MultipartForm", "SaveUploadedFile":
+						info.multipart = true
+
+					case "GetRawData":
+						info.rawBody = true
+
+					case "AbortWithError", "AbortWithStatus":
+						if len(callexpr.Args) > 0 {
+							if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
+								d := "description"
+								info.responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
+									Value: &openapi3.Response{
+										Description: &d,
+									},
+								}
+							}
+						}
+
+					case "AbortWithStatusJSON", "AsciiJSON", "IndentedJSON", "JSON", "PureJSON", "SecureJSON":
+						if len(callexpr.Args) > 1 {
+							if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
+								arg1 := v.typeOf(callexpr.Args[1], pkg)
+								d := "description"
+								info.responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
+									Value: &openapi3.Response{
+										Description: &d,
+										Content: openapi3.Content{
+											"application/json": &openapi3.MediaType{
+												Schema: v.schemas.ToSchemaRefFor(arg1, "json", Response),
+											},
+										},
+									},
+								}
+							}
+						}
+
+					case "Data":
+						if len(callexpr.Args) > 1 {
+							if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
+								if contentType, ok := v.foldStringConstant(callexpr.Args[1], pkg); ok {
+									d := "description"
+									info.responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
+										Value: &openapi3.Response{
+											Description: &d,
+											Content: openapi3.Content{
+												contentType: &openapi3.MediaType{},
+											},
+										},
+									}
+								}
+							}
+						}
+
+					case "DataFromReader":
+						if len(callexpr.Args) > 2 {
+							if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
+								if contentType, ok := v.foldStringConstant(callexpr.Args[2], pkg); ok {
+									d := "description"
+									info.responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
+										Value: &openapi3.Response{
+											Description: &d,
+											Content: openapi3.Content{
+												contentType: &openapi3.MediaType{},
+											},
+										},
+									}
+								}
+							}
+						}
+
+					case "HTML", "Render":
+						if len(callexpr.Args) > 0 {
+							if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
+								d := "description"
+								info.responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
+									Value: &openapi3.Response{
+										Description: &d,
+										Content: openapi3.Content{
+											"text/html": &openapi3.MediaType{},
+										},
+									},
+								}
+							}
+						}
+
+					case "JSONP":
+						if len(callexpr.Args) > 1 {
+							if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
+								arg1 := v.typeOf(callexpr.Args[1], pkg)
+								d := "description"
+								info.responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
+									Value: &openapi3.Response{
+										Description: &d,
+										Content: openapi3.Content{
+											"application/javascript": &openapi3.MediaType{
+												Schema: v.schemas.ToSchemaRefFor(arg1, "json", Response),
+											},
+										},
+									},
+								}
+							}
+						}
+
+					case "Redirect", "Status", "String":
+						if len(callexpr.Args) > 0 {
+							if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
+								d := "description"
+								info.responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
+									Value: &openapi3.Response{
+										Description: &d,
+									},
+								}
+							}
+						}
+
+					case "XML":
+						if len(callexpr.Args) > 1 {
+							if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
+								arg1 := v.typeOf(callexpr.Args[1], pkg)
+								d := "description"
+								info.responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
+									Value: &openapi3.Response{
+										Description: &d,
+										Content: openapi3.Content{
+											"text/xml": &openapi3.MediaType{
+												Schema: v.schemas.ToSchemaRefFor(arg1, "xml", Response),
+											},
+										},
+									},
+								}
+							}
+						}
+
+					case "YAML":
+						if len(callexpr.Args) > 1 {
+							if status, ok := v.foldIntConstant(callexpr.Args[0], pkg); ok {
+								arg1 := v.typeOf(callexpr.Args[1], pkg)
+								d := "description"
+								info.responses[strconv.Itoa(status)] = &openapi3.ResponseRef{
+									Value: &openapi3.Response{
+										Description: &d,
+										Content: openapi3.Content{
+											"text/yaml": &openapi3.MediaType{
+												Schema: v.schemas.ToSchemaRefFor(arg1, "yaml", Response),
+											},
+										},
+									},
+								}
+							}
+						}
+
+					}
+					return false
+				}
+			}
+
+			v.inspectRequest(callexpr, pkg, info)
+			v.inspectBody(callexpr, pkg, info)
+			v.inspectConversion(callexpr, pkg, info)
+
+			// Helpers taking the gin context are followed, whatever they
+			// infer is attributed to the calling endpoint.
+			v.inspectHelper(callexpr, pkg, info)
+		}
+
+		return true
+	})
+}
+
+// typeOf returns the type of expr, looking through the bindings when it is an
+// interface, e.g. the interface{} parameter of a helper wrapping c.JSON.
+func (v *EndpointsVisitor) typeOf(expr ast.Expr, pkg *packages.Package) types.Type {
+	ty := pkg.TypesInfo.TypeOf(expr)
+	if ty == nil || !types.IsInterface(ty) {
+		return ty
+	}
+
+	if ident, ok := unparen(expr).(*ast.Ident); ok {
+		if resolved, rpkg := v.resolveExpr(ident, pkg); resolved != ident {
+			if concrete := v.typeOf(resolved, rpkg); concrete != nil {
+				return concrete
+			}
+		}
+	}
+
+	return ty
+}
+
+func (v *EndpointsVisitor) inspectHelper(callexpr *ast.CallExpr, pkg *packages.Package, info *handlerInfo) {
+	var follow bool
+	for _, arg := range callexpr.Args {
+		if isGinContext(pkg.TypesInfo.TypeOf(arg)) {
+			follow = true
+			break
+		}
+	}
+
+	if !follow {
+		return
+	}
+
+	fdecl, fpkg := v.resolveFunctionDeclaration(callexpr, pkg)
+	if fdecl == nil || fdecl.Body == nil || info.visiting[fdecl] {
+		return
+	}
+
+	info.visiting[fdecl] = true
+	v.bindParams(fdecl, fpkg, callexpr, pkg)
+	v.inspectHandler(fdecl.Body, fpkg, info)
+	delete(info.visiting, fdecl)
+}
+
+// handlerBody is the body of a function implementing a gin handler, along with
+// the package holding its type information.
+type handlerBody struct {
+	body *ast.BlockStmt
+	pkg  *packages.Package
+}
+
+// resolveHandler follows a handler expression (inline closure, variable,
+// function or method value, or call to a handler factory) to the bodies
+// implementing it.
+func (v *EndpointsVisitor) resolveHandler(expr ast.Expr, pkg *packages.Package, seen map[ast.Node]bool) []handlerBody {
+	if expr == nil || seen[expr] {
+		return nil
+	}
+	seen[expr] = true
+
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return v.resolveHandler(e.X, pkg, seen)
+
+	case *ast.FuncLit:
+		return []handlerBody{{e.Body, pkg}}
+
+	case *ast.Ident:
+		if fn, ok := pkg.TypesInfo.ObjectOf(e).(*types.Func); ok {
+			return v.resolveFuncBody(fn)
+		}
+		if resolved, rpkg := v.resolveExpr(e, pkg); resolved != e {
+			return v.resolveHandler(resolved, rpkg, seen)
+		}
+
+	case *ast.SelectorExpr:
+		if fn, ok := pkg.TypesInfo.ObjectOf(e.Sel).(*types.Func); ok {
+			return v.resolveFuncBody(fn)
+		}
+		if resolved, rpkg := v.resolveSelector(e, pkg); resolved != nil {
+			return v.resolveHandler(resolved, rpkg, seen)
+		}
+
+	case *ast.CallExpr:
+		// conversions such as gin.HandlerFunc(fn)
+		if tv, ok := pkg.TypesInfo.Types[e.Fun]; ok && tv.IsType() {
+			if len(e.Args) == 1 {
+				return v.resolveHandler(e.Args[0], pkg, seen)
+			}
+			return nil
+		}
+
+		// handler factories: bind the arguments, then follow whatever they return
+		fdecl, fpkg := v.resolveFuncDecl(calledFunc(e, pkg))
+		if fdecl == nil || fdecl.Body == nil || seen[fdecl] {
+			return nil
+		}
+		seen[fdecl] = true
+
+		v.bindParams(fdecl, fpkg, e, pkg)
+
+		var out []handlerBody
+		ast.Inspect(fdecl.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false // returns from nested closures are not ours
+			case *ast.ReturnStmt:
+				if len(n.Results) == 1 {
+					out = append(out, v.resolveHandler(n.Results[0], fpkg, seen)...)
 				}
+				return false
+			}
+			v.recordBinding(n, fpkg)
+			return true
+		})
+		return out
+	}
+
+	return nil
+}
+
+func (v *EndpointsVisitor) resolveFuncBody(fn *types.Func) []handlerBody {
+	if fdecl, fpkg := v.resolveFuncDecl(fn); fdecl != nil && fdecl.Body != nil {
+		return []handlerBody{{fdecl.Body, fpkg}}
+	}
+	return nil
+}
+
+// calledFunc returns the function or method statically called by callexpr, if
+// any.
+func calledFunc(callexpr *ast.CallExpr, pkg *packages.Package) *types.Func {
+	switch fun := callexpr.Fun.(type) {
+	case *ast.Ident:
+		fn, _ := pkg.TypesInfo.ObjectOf(fun).(*types.Func)
+		return fn
+	case *ast.SelectorExpr:
+		fn, _ := pkg.TypesInfo.ObjectOf(fun.Sel).(*types.Func)
+		return fn
+	}
+	return nil
+}
+
+// resolveFuncDecl finds the declaration of a function or method within the
+// loaded packages by matching its position.
+func (v *EndpointsVisitor) resolveFuncDecl(fn *types.Func) (*ast.FuncDecl, *packages.Package) {
+	if fn == nil || fn.Pkg() == nil {
+		return nil, nil
+	}
+
+	fpkg := v.pkgsByID[fn.Pkg().Path()]
+	if fpkg == nil {
+		return nil, nil
+	}
+
+	for _, file := range fpkg.Syntax {
+		if fn.Pos() < file.Pos() || fn.Pos() >= file.End() {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if fdecl, ok := decl.(*ast.FuncDecl); ok && fdecl.Name.Pos() == fn.Pos() {
+				return fdecl, fpkg
 			}
 		}
 	}
+
 	return nil, nil
 }
 
-func (v *EndpointsVisitor) resolveExpr(x *ast.Ident) ast.Expr {
-	if x.Obj != nil {
-		if resolved, ok := v.exprsByIdent[*x.Obj]; ok {
-			if ident, ok := resolved.(*ast.Ident); ok {
-				return v.resolveExpr(ident)
+// resolveFunctionDeclaration finds the declaration of the function or method
+// called by callexpr. Calls through an interface are resolved to the concrete
+// method when a single type of the loaded packages implements it.
+func (v *EndpointsVisitor) resolveFunctionDeclaration(callexpr *ast.CallExpr, pkg *packages.Package) (*ast.FuncDecl, *packages.Package) {
+	fn := calledFunc(callexpr, pkg)
+	if fn == nil {
+		return nil, nil
+	}
+
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		if iface, ok := recv.Type().Underlying().(*types.Interface); ok {
+			fn = v.resolveImplementation(iface, fn)
+		}
+	}
+
+	return v.resolveFuncDecl(fn)
+}
+
+// resolveImplementation returns the concrete method implementing the interface
+// method fn, as long as there is a single implementation of iface within the
+// loaded packages.
+func (v *EndpointsVisitor) resolveImplementation(iface *types.Interface, fn *types.Func) *types.Func {
+	var impl *types.Func
+
+	for _, pkg := range v.pkgsByID {
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || tn.IsAlias() || types.IsInterface(tn.Type()) {
+				continue
+			}
+
+			var ty types.Type = tn.Type()
+			if !types.Implements(ty, iface) {
+				ty = types.NewPointer(ty)
+				if !types.Implements(ty, iface) {
+					continue
+				}
+			}
+
+			if impl != nil {
+				return nil // ambiguous
+			}
+
+			obj, _, _ := types.LookupFieldOrMethod(ty, true, fn.Pkg(), fn.Name())
+			if impl, ok = obj.(*types.Func); !ok {
+				return nil
+			}
+		}
+	}
+
+	return impl
+}
+
+// recordBinding stores the expressions assigned, declared or set as struct
+// fields by n, returning false if n does not bind anything.
+func (v *EndpointsVisitor) recordBinding(n ast.Node, pkg *packages.Package) bool {
+	switch n := n.(type) {
+	case *ast.AssignStmt:
+		for i, lhs := range n.Lhs {
+			if i >= len(n.Rhs) {
+				break
+			}
+			var ident *ast.Ident
+			switch lhs := lhs.(type) {
+			case *ast.Ident:
+				ident = lhs
+			case *ast.SelectorExpr:
+				ident = lhs.Sel // struct fields, e.g. s.router = gin.New()
+			default:
+				continue
+			}
+			obj := pkg.TypesInfo.ObjectOf(ident)
+			if obj == nil {
+				continue
+			}
+
+			switch n.Tok {
+			case token.ASSIGN, token.DEFINE:
+				// p = p + "/v2" binds p to its previous value plus "/v2"
+				rhs := n.Rhs[i]
+				if prev, ok := v.exprsByObj[obj]; ok && prev.pkg == pkg {
+					rhs = substitute(rhs, obj, prev.expr, pkg)
+				}
+				v.exprsByObj[obj] = boundExpr{rhs, pkg}
+			case token.ADD_ASSIGN:
+				// p += "/v1" binds p to its previous value plus "/v1"
+				var x ast.Expr = lhs
+				if prev, ok := v.exprsByObj[obj]; ok && prev.pkg == pkg {
+					x = prev.expr
+				}
+				v.exprsByObj[obj] = boundExpr{&ast.BinaryExpr{X: x, OpPos: n.TokPos, Op: token.ADD, Y: n.Rhs[i]}, pkg}
+			default:
+				delete(v.exprsByObj, obj)
+			}
+		}
+		return true
+
+	case *ast.CompositeLit:
+		// struct fields, e.g. &Server{router: gin.New()}
+		if ty := pkg.TypesInfo.TypeOf(n); ty != nil && isStruct(ty) {
+			for _, elt := range n.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok {
+						if obj := pkg.TypesInfo.ObjectOf(key); obj != nil {
+							v.exprsByObj[obj] = boundExpr{kv.Value, pkg}
+						}
+					}
+				}
+			}
+		}
+		return true
+
+	case *ast.ValueSpec:
+		for i, ident := range n.Names {
+			if i >= len(n.Values) {
+				break
+			}
+			if obj := pkg.TypesInfo.ObjectOf(ident); obj != nil {
+				v.exprsByObj[obj] = boundExpr{n.Values[i], pkg}
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// bindParams binds the receiver and parameters of fdecl to the expressions
+// passed by callexpr, so they can be resolved while walking its body.
+func (v *EndpointsVisitor) bindParams(fdecl *ast.FuncDecl, fpkg *packages.Package, callexpr *ast.CallExpr, pkg *packages.Package) {
+	if fdecl.Recv != nil && len(fdecl.Recv.List) == 1 && len(fdecl.Recv.List[0].Names) == 1 {
+		if selectorexpr, ok := callexpr.Fun.(*ast.SelectorExpr); ok {
+			if obj := fpkg.TypesInfo.ObjectOf(fdecl.Recv.List[0].Names[0]); obj != nil {
+				v.exprsByObj[obj] = boundExpr{selectorexpr.X, pkg}
 			}
-			return resolved
 		}
 	}
-	return x
+
+	i := 0
+	for _, param := range fdecl.Type.Params.List {
+		for _, name := range param.Names {
+			if i >= len(callexpr.Args) {
+				return
+			}
+			if obj := fpkg.TypesInfo.ObjectOf(name); obj != nil {
+				v.exprsByObj[obj] = boundExpr{callexpr.Args[i], pkg}
+			}
+			i++
+		}
+	}
+}
+
+// substitute returns a copy of expr in which the identifiers referring to obj
+// are replaced by with, or expr itself when it does not refer to obj. Only the
+// expressions that can be folded are looked into.
+func substitute(expr ast.Expr, obj types.Object, with ast.Expr, pkg *packages.Package) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if pkg.TypesInfo.ObjectOf(e) == obj {
+			return with
+		}
+
+	case *ast.ParenExpr:
+		if x := substitute(e.X, obj, with, pkg); x != e.X {
+			copied := *e
+			copied.X = x
+			return &copied
+		}
+
+	case *ast.BinaryExpr:
+		x, y := substitute(e.X, obj, with, pkg), substitute(e.Y, obj, with, pkg)
+		if x != e.X || y != e.Y {
+			copied := *e
+			copied.X, copied.Y = x, y
+			return &copied
+		}
+
+	case *ast.CallExpr:
+		args := make([]ast.Expr, len(e.Args))
+		substituted := false
+		for i, arg := range e.Args {
+			args[i] = substitute(arg, obj, with, pkg)
+			substituted = substituted || args[i] != arg
+		}
+		if substituted {
+			copied := *e
+			copied.Args = args
+			return &copied
+		}
+	}
+
+	return expr
+}
+
+// unrollRange walks the body of a range loop once per element of the
+// composite literal it iterates over, with the loop variables bound to that
+// element. It returns false if the collection is not statically known.
+func (v *EndpointsVisitor) unrollRange(rangestmt *ast.RangeStmt, pkg *packages.Package) bool {
+	x, xpkg := rangestmt.X, pkg
+	if ident, ok := x.(*ast.Ident); ok {
+		x, xpkg = v.resolveExpr(ident, pkg)
+	}
+
+	lit, ok := unparen(x).(*ast.CompositeLit)
+	if !ok {
+		return false
+	}
+
+	var isMap bool
+	switch xpkg.TypesInfo.TypeOf(lit).Underlying().(type) {
+	case *types.Map:
+		isMap = true
+	case *types.Slice, *types.Array:
+	default:
+		return false
+	}
+
+	bind := func(lhs ast.Expr, expr ast.Expr) {
+		if ident, ok := lhs.(*ast.Ident); ok {
+			if obj := pkg.TypesInfo.ObjectOf(ident); obj != nil {
+				if expr != nil {
+					v.exprsByObj[obj] = boundExpr{expr, xpkg}
+				} else {
+					delete(v.exprsByObj, obj)
+				}
+			}
+		}
+	}
+
+	for _, elt := range lit.Elts {
+		var key, value ast.Expr = nil, elt
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			value = kv.Value
+			if isMap {
+				key = kv.Key
+			}
+		}
+
+		bind(rangestmt.Key, key)
+		bind(rangestmt.Value, value)
+		v.walk(rangestmt.Body, pkg)
+	}
+
+	return true
+}
+
+// resolveSelector resolves x.f to the expression the field f was set to, when
+// x stands for a struct composite literal.
+func (v *EndpointsVisitor) resolveSelector(sel *ast.SelectorExpr, pkg *packages.Package) (ast.Expr, *packages.Package) {
+	x, xpkg := sel.X, pkg
+	if ident, ok := x.(*ast.Ident); ok {
+		x, xpkg = v.resolveExpr(ident, pkg)
+	}
+
+	x = unparen(x)
+	if unary, ok := x.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		x = unparen(unary.X)
+	}
+
+	lit, ok := x.(*ast.CompositeLit)
+	if !ok {
+		return nil, nil
+	}
+
+	// elided &T in []*T{{...}} leaves the literal typed as *T
+	strct, ok := flattenPointers(xpkg.TypesInfo.TypeOf(lit)).Underlying().(*types.Struct)
+	if !ok {
+		return nil, nil
+	}
+
+	for i, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == sel.Sel.Name {
+				return kv.Value, xpkg
+			}
+		} else if i < strct.NumFields() && strct.Field(i).Name() == sel.Sel.Name {
+			return elt, xpkg
+		}
+	}
+
+	return nil, nil
+}
+
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.X
+	}
+}
+
+// resolveExpr follows identifiers through the assignments, declarations and
+// bound parameters seen so far, returning the expression they stand for along
+// with its package.
+func (v *EndpointsVisitor) resolveExpr(x *ast.Ident, pkg *packages.Package) (ast.Expr, *packages.Package) {
+	seen := map[types.Object]bool{}
+	for {
+		obj := pkg.TypesInfo.ObjectOf(x)
+		if obj == nil || seen[obj] {
+			return x, pkg
+		}
+		seen[obj] = true
+
+		resolved, ok := v.exprsByObj[obj]
+		if !ok {
+			return x, pkg
+		}
+
+		switch e := resolved.expr.(type) {
+		case *ast.Ident:
+			x, pkg = e, resolved.pkg
+		case *ast.SelectorExpr:
+			// struct fields are followed to whatever they were set to
+			if field, ok := resolved.pkg.TypesInfo.ObjectOf(e.Sel).(*types.Var); ok && field.IsField() {
+				x, pkg = e.Sel, resolved.pkg
+			} else {
+				return resolved.expr, resolved.pkg
+			}
+		default:
+			return resolved.expr, resolved.pkg
+		}
+	}
 }
 
 var inferPathRegexp = regexp.MustCompilePOSIX(`\/[*:][^\/]+`)
@@ -562,14 +1685,21 @@ func inferPath(input string) (string, openapi3.Parameters) {
 	params := openapi3.Parameters{}
 
 	path := inferPathRegexp.ReplaceAllStringFunc(input, func(match string) string {
-		required := match[1] == ':'
 		name := match[2:]
 
+		// path parameters are always required, even catch-alls matching an
+		// empty rest of the path
+		var description string
+		if match[1] == '*' {
+			description = "the rest of the path, possibly empty"
+		}
+
 		params = append(params, &openapi3.ParameterRef{
 			Value: &openapi3.Parameter{
-				In:       openapi3.ParameterInPath,
-				Name:     name,
-				Required: required,
+				In:          openapi3.ParameterInPath,
+				Name:        name,
+				Description: description,
+				Required:    true,
 				Schema: &openapi3.SchemaRef{
 					Value: &openapi3.Schema{
 						Type: openapi3.TypeString,
@@ -624,66 +1754,60 @@ func resolveGinKind(ty types.Type) GinKind {
 	return Unknown
 }
 
-func isGinContext(ty types.Type) bool {
-	return ty != nil && ty.String() == "*github.com/gin-gonic/gin.Context"
-}
-
-func paramsFromStructFields(ty types.Type, tag string, in string) openapi3.Parameters {
-	var out openapi3.Parameters
-
-	if ty == nil {
-		return out
-	}
-
-	for {
-		if ptr, ok := ty.(*types.Pointer); ok {
-			ty = ptr.Elem()
-		} else if named, ok := ty.(*types.Named); ok {
-			ty = named.Underlying()
-		} else {
-			break
-		}
+// hasGinField reports whether ty is (a pointer to) a struct holding a gin
+// engine or router group.
+func hasGinField(ty types.Type) bool {
+	if ptr, ok := ty.(*types.Pointer); ok {
+		ty = ptr.Elem()
 	}
 
-	if strct, ok := ty.(*types.Struct); ok {
+	if strct, ok := ty.Underlying().(*types.Struct); ok {
 		for i, max := 0, strct.NumFields(); i < max; i++ {
-			if f := strct.Field(i); f.Exported() {
-				tags, err := structtag.Parse(strct.Tag(i))
-				if err != nil {
-					continue
-				}
-
-				for _, key := range tags.Keys() {
-					if key == tag {
-						if value, err := tags.Get(tag); err == nil {
-							out = append(out, &openapi3.ParameterRef{
-								Value: &openapi3.Parameter{
-									In:       in,
-									Name:     value.Name,
-									Required: false,
-									Schema: &openapi3.SchemaRef{
-										Value: &openapi3.Schema{
-											Type: openapi3.TypeString,
-										},
-									},
-								},
-							})
-						}
-						continue
-					}
-				}
+			if kind := resolveGinKind(strct.Field(i).Type()); kind != Unknown {
+				return true
 			}
 		}
 	}
 
-	return out
+	return false
+}
+
+func isStruct(ty types.Type) bool {
+	_, ok := ty.Underlying().(*types.Struct)
+	return ok
+}
+
+func isGinContext(ty types.Type) bool {
+	return ty != nil && ty.String() == "*github.com/gin-gonic/gin.Context"
 }
 
 type Group struct {
-	Path      string
-	Params    openapi3.Parameters
-	groups    []*Group
-	endpoints []*Endpoint
+	Path        string
+	Params      openapi3.Parameters
+	groups      []*Group
+	endpoints   []*Endpoint
+	middlewares []boundExpr // handlers prepended to routes registered from now on
+	noRoute     []boundExpr
+	noMethod    []boundExpr
+}
+
+// each calls fn for every endpoint of the group and its sub-groups.
+func (g *Group) each(fn func(*Endpoint)) {
+	for _, endpoint := range g.endpoints {
+		fn(endpoint)
+	}
+	for _, group := range g.groups {
+		group.each(fn)
+	}
+}
+
+// combineHandlers returns the group middlewares followed by handlers, mirroring
+// gin's RouterGroup.combineHandlers: the result is a snapshot, so middlewares
+// added later on do not apply to it.
+func (g *Group) combineHandlers(handlers []boundExpr) []boundExpr {
+	out := make([]boundExpr, 0, len(g.middlewares)+len(handlers))
+	out = append(out, g.middlewares...)
+	return append(out, handlers...)
 }
 
 func (g *Group) all() []*Endpoint {
@@ -707,4 +1831,5 @@ type Endpoint struct {
 	Responses   openapi3.Responses
 	Method      string
 	Description string
+	uriParams   openapi3.Parameters
 }
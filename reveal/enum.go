@@ -0,0 +1,256 @@
+package reveal
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+// enumValue is a constant of a named basic type, as it is written.
+type enumValue struct {
+	name  string // of the constant
+	value interface{}
+	doc   string
+}
+
+// enumValues lists the constants declared with type named, in declaration
+// order. When method is not nil, the values are what its switch over the
+// constants returns, e.g. for a MarshalText method. Values declared several
+// times are listed once, under their first name.
+func (v *EndpointsVisitor) enumValues(named *types.Named, method *types.Func) []enumValue {
+	consts := constantsOf(named)
+	if len(consts) == 0 {
+		return nil
+	}
+
+	var texts map[*types.Const]string
+	if method != nil {
+		var ok bool
+		if texts, ok = v.switchStrings(method, map[*types.Func]bool{}); !ok {
+			return nil
+		}
+	} else if stringer := stringMethod(named); stringer != nil {
+		// String() is not what is written, but it describes the values
+		texts, _ = v.switchStrings(stringer, map[*types.Func]bool{})
+	}
+
+	var out []enumValue
+	seen := map[interface{}]bool{}
+	for _, c := range consts {
+		value := constantToInterface(c.Val())
+		doc := v.constDoc(c)
+		if method != nil {
+			text, ok := texts[c]
+			if !ok {
+				continue
+			}
+			value = text
+		} else if doc == "" {
+			doc = texts[c]
+		}
+
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+
+		out = append(out, enumValue{name: c.Name(), value: value, doc: doc})
+	}
+
+	return out
+}
+
+// constantsOf returns the package-level constants of type named, in
+// declaration order. Unexported constants (e.g. counters) are left out unless
+// the type is unexported itself.
+func constantsOf(named *types.Named) []*types.Const {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return nil
+	}
+
+	var out []*types.Const
+	scope := obj.Pkg().Scope()
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !types.Identical(c.Type(), named) {
+			continue
+		}
+		if obj.Exported() && !c.Exported() {
+			continue
+		}
+		out = append(out, c)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Pos() < out[j].Pos()
+	})
+
+	return out
+}
+
+// stringMethod returns the String() string method of named, if any.
+func stringMethod(named *types.Named) *types.Func {
+	sel := types.NewMethodSet(types.NewPointer(named)).Lookup(named.Obj().Pkg(), "String")
+	if sel == nil || len(sel.Index()) > 1 {
+		return nil
+	}
+
+	method, ok := sel.Obj().(*types.Func)
+	if !ok {
+		return nil
+	}
+
+	sig := method.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 || !types.Identical(sig.Results().At(0).Type(), types.Typ[types.String]) {
+		return nil
+	}
+
+	return method
+}
+
+// switchStrings maps the constants a method switches over to the strings it
+// returns for them, e.g.
+//
+//	switch s {
+//	case StatusPending:
+//		return "pending"
+//	}
+//
+// A method whose body only returns the result of another method of its
+// receiver, e.g. []byte(s.String()), is followed. The boolean is false when
+// the method is not such a switch.
+func (v *EndpointsVisitor) switchStrings(method *types.Func, seen map[*types.Func]bool) (map[*types.Const]string, bool) {
+	if seen[method] {
+		return nil, false
+	}
+	seen[method] = true
+
+	decl, pkg := v.resolveFuncDecl(method)
+	if decl == nil || decl.Body == nil || decl.Recv == nil {
+		return nil, false
+	}
+	recv := method.Type().(*types.Signature).Recv()
+
+	for _, stmt := range decl.Body.List {
+		switch stmt := stmt.(type) {
+		case *ast.SwitchStmt:
+			if stmt.Init != nil || stmt.Tag == nil {
+				return nil, false
+			}
+			if ty := pkg.TypesInfo.TypeOf(stmt.Tag); ty == nil || !types.Identical(flattenPointers(ty), flattenPointers(recv.Type())) {
+				return nil, false
+			}
+			return v.caseStrings(stmt, pkg), true
+
+		case *ast.ReturnStmt:
+			if len(stmt.Results) == 0 {
+				return nil, false
+			}
+			call, ok := unparen(unconvert(stmt.Results[0], pkg)).(*ast.CallExpr)
+			if !ok || len(call.Args) > 0 {
+				return nil, false
+			}
+			delegate := calledFunc(call, pkg)
+			if delegate == nil {
+				return nil, false
+			}
+			if sig := delegate.Type().(*types.Signature); sig.Recv() == nil || !types.Identical(flattenPointers(sig.Recv().Type()), flattenPointers(recv.Type())) {
+				return nil, false
+			}
+			return v.switchStrings(delegate, seen)
+		}
+	}
+
+	return nil, false
+}
+
+// caseStrings maps the constants of the cases of stmt returning a string
+// constant to that string.
+func (v *EndpointsVisitor) caseStrings(stmt *ast.SwitchStmt, pkg *packages.Package) map[*types.Const]string {
+	out := map[*types.Const]string{}
+	for _, clause := range stmt.Body.List {
+		clause, ok := clause.(*ast.CaseClause)
+		if !ok || len(clause.Body) != 1 {
+			continue
+		}
+		ret, ok := clause.Body[0].(*ast.ReturnStmt)
+		if !ok || len(ret.Results) == 0 {
+			continue
+		}
+		text, ok := v.foldStringConstant(unconvert(ret.Results[0], pkg), pkg)
+		if !ok {
+			continue
+		}
+
+		for _, expr := range clause.List {
+			var ident *ast.Ident
+			switch expr := unparen(expr).(type) {
+			case *ast.Ident:
+				ident = expr
+			case *ast.SelectorExpr:
+				ident = expr.Sel
+			}
+			if ident == nil {
+				continue
+			}
+			if c, ok := pkg.TypesInfo.ObjectOf(ident).(*types.Const); ok {
+				out[c] = text
+			}
+		}
+	}
+	return out
+}
+
+// unconvert strips a type conversion, e.g. []byte("pending").
+func unconvert(expr ast.Expr, pkg *packages.Package) ast.Expr {
+	call, ok := unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return expr
+	}
+	if tv, ok := pkg.TypesInfo.Types[call.Fun]; !ok || !tv.IsType() {
+		return expr
+	}
+	return call.Args[0]
+}
+
+// constDoc returns the doc (or line) comment of a constant, or the one of its
+// declaration when it declares it alone.
+func (v *EndpointsVisitor) constDoc(c *types.Const) string {
+	pkg := v.pkgsByID[c.Pkg().Path()]
+	if pkg == nil {
+		return ""
+	}
+
+	for _, file := range pkg.Syntax {
+		if c.Pos() < file.Pos() || c.Pos() >= file.End() {
+			continue
+		}
+
+		path, _ := astutil.PathEnclosingInterval(file, c.Pos(), c.Pos())
+		for i, node := range path {
+			spec, ok := node.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			if spec.Doc != nil {
+				return strings.TrimSpace(spec.Doc.Text())
+			}
+			if spec.Comment != nil {
+				return strings.TrimSpace(spec.Comment.Text())
+			}
+			if i+1 < len(path) {
+				if decl, ok := path[i+1].(*ast.GenDecl); ok && len(decl.Specs) == 1 && decl.Doc != nil {
+					return strings.TrimSpace(decl.Doc.Text())
+				}
+			}
+			return ""
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,167 @@
+package reveal
+
+import (
+	"go/types"
+	"sort"
+
+	"github.com/fatih/structtag"
+)
+
+// structField is a field serialized as a property of a struct.
+type structField struct {
+	name       string
+	field      *types.Var
+	tags       *structtag.Tags
+	tagged     bool // named by the tag rather than after the field
+	depth      int  // embedding depth
+	viaPointer bool // promoted through an embedded pointer
+	omitempty  bool
+	index      []int // path of field indexes, like reflect's
+}
+
+// structFields lists the properties of strct as encoding/json sees them with
+// the given tag: the fields of untagged embedded structs are promoted, the
+// shallowest field of a given name wins, then the tagged one, and ambiguous
+// names are dropped.
+func structFields(strct *types.Struct, tag string) []structField {
+	type level struct {
+		strct      *types.Struct
+		index      []int
+		viaPointer bool
+	}
+
+	var fields []structField
+	current, next := []level{}, []level{{strct, nil, false}}
+	visited := map[*types.Struct]bool{}
+	count, nextCount := map[*types.Struct]int{}, map[*types.Struct]int{strct: 1}
+
+	for depth := 0; len(next) > 0; depth++ {
+		current, next = next, nil
+		count, nextCount = nextCount, map[*types.Struct]int{}
+
+		for _, l := range current {
+			if visited[l.strct] {
+				continue
+			}
+			visited[l.strct] = true
+
+			for i := 0; i < l.strct.NumFields(); i++ {
+				f := l.strct.Field(i)
+
+				ty := flattenPointers(f.Type())
+				_, isPointer := f.Type().(*types.Pointer)
+				embedded, isStruct := ty.Underlying().(*types.Struct)
+				isStruct = isStruct && !isWellKnown(ty)
+
+				if f.Embedded() {
+					if !f.Exported() && !isStruct {
+						continue
+					}
+				} else if !f.Exported() {
+					continue
+				}
+
+				tags, err := structtag.Parse(l.strct.Tag(i))
+				if err != nil {
+					tags = &structtag.Tags{}
+				}
+
+				name, omitempty, skipped := "", false, false
+				if value, err := tags.Get(tag); err == nil {
+					name, omitempty = value.Name, value.HasOption("omitempty")
+					skipped = name == "-" && len(value.Options) == 0 // "-," names it "-"
+				}
+				if skipped {
+					continue
+				}
+
+				if name == "" && f.Embedded() && isStruct {
+					nextCount[embedded]++
+					if nextCount[embedded] == 1 {
+						next = append(next, level{embedded, appendIndex(l.index, i), l.viaPointer || isPointer})
+					}
+					continue
+				}
+
+				field := structField{
+					name:       name,
+					field:      f,
+					tags:       tags,
+					tagged:     name != "",
+					depth:      depth,
+					viaPointer: l.viaPointer,
+					omitempty:  omitempty,
+					index:      appendIndex(l.index, i),
+				}
+				if field.name == "" {
+					field.name = f.Name()
+				}
+				fields = append(fields, field)
+
+				// the same struct embedded twice at this depth annihilates
+				// its fields, as they are ambiguous
+				if count[l.strct] > 1 {
+					fields = append(fields, field)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+		if fields[i].depth != fields[j].depth {
+			return fields[i].depth < fields[j].depth
+		}
+		return fields[i].tagged && !fields[j].tagged
+	})
+
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		// fields sharing a name
+		advance = 1
+		for advance < len(fields)-i && fields[i+advance].name == fields[i].name {
+			advance++
+		}
+		if dominant, ok := dominantField(fields[i : i+advance]); ok {
+			out = append(out, dominant)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i].index, out[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	return out
+}
+
+func appendIndex(index []int, i int) []int {
+	return append(append([]int{}, index...), i)
+}
+
+// alwaysSerialized reports whether the field is written whatever its value.
+func (f structField) alwaysSerialized() bool {
+	if f.viaPointer {
+		return false
+	}
+	if _, ok := f.field.Type().(*types.Pointer); ok {
+		return false
+	}
+	return !f.omitempty
+}
+
+// dominantField picks the field winning among fields of the same name, sorted
+// by depth then tagging.
+func dominantField(fields []structField) (structField, bool) {
+	if len(fields) > 1 && fields[0].depth == fields[1].depth && fields[0].tagged == fields[1].tagged {
+		return structField{}, false
+	}
+	return fields[0], true
+}
@@ -0,0 +1,134 @@
+package reveal
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+// checkType type-checks the declarations of src, which may not import
+// anything, and returns the type named T.
+func checkType(t *testing.T, src string) types.Type {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, "src.go", "package p\n"+src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, err := new(types.Config).Check("p", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := pkg.Scope().Lookup("T")
+	if obj == nil {
+		t.Fatal("no type T declared")
+	}
+	return obj.Type()
+}
+
+func TestStructFields(t *testing.T) {
+	// properties are listed by name, suffixed with ? when they may be left
+	// out of the output
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "tags rename and skip fields",
+			src:  "type T struct { A int; B int `json:\"b\"`; c int; D int `json:\"-\"`; E int `json:\"-,\"` }",
+			want: []string{"A", "b", "-"},
+		},
+		{
+			name: "omitempty and pointers may be left out",
+			src:  "type T struct { A int `json:\"a,omitempty\"`; B *int }",
+			want: []string{"a?", "B?"},
+		},
+		{
+			name: "embedded structs are promoted",
+			src:  "type E struct { X int }; type T struct { A int; E; B int }",
+			want: []string{"A", "X", "B"},
+		},
+		{
+			name: "unexported embedded structs are promoted",
+			src:  "type e struct { X int }; type T struct { e }",
+			want: []string{"X"},
+		},
+		{
+			name: "tagged embedded structs are not promoted",
+			src:  "type E struct { X int }; type T struct { E `json:\"e\"` }",
+			want: []string{"e"},
+		},
+		{
+			name: "fields promoted through pointers may be left out",
+			src:  "type E struct { X int }; type T struct { *E }",
+			want: []string{"X?"},
+		},
+		{
+			name: "the shallowest field wins",
+			src:  "type E struct { X int }; type T struct { E; X string }",
+			want: []string{"X"},
+		},
+		{
+			name: "the tagged field wins at the same depth",
+			src:  "type E1 struct { X int `json:\"X\"` }; type E2 struct { X int }; type T struct { E1; E2 }",
+			want: []string{"X"},
+		},
+		{
+			name: "ambiguous fields are dropped",
+			src:  "type E1 struct { X int; Y int }; type E2 struct { X int }; type T struct { E1; E2 }",
+			want: []string{"Y"},
+		},
+		{
+			name: "a struct embedded twice at the same depth is ambiguous",
+			src:  "type E struct { X int }; type A struct { E }; type B struct { E }; type T struct { A; B }",
+			want: []string{},
+		},
+		{
+			name: "recursive embedding terminates",
+			src:  "type T struct { *T; X int }",
+			want: []string{"X"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			strct := checkType(t, test.src).Underlying().(*types.Struct)
+
+			got := []string{}
+			for _, f := range structFields(strct, "json") {
+				name := f.name
+				if !f.alwaysSerialized() {
+					name += "?"
+				}
+				got = append(got, name)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStructFieldsDominance(t *testing.T) {
+	src := "type E struct { X int; Y int `json:\"Y\"` }; type T struct { E; X string; Y bool }"
+	strct := checkType(t, src).Underlying().(*types.Struct)
+
+	got := map[string]string{}
+	for _, f := range structFields(strct, "json") {
+		got[f.name] = f.field.Type().String()
+	}
+
+	// the fields declared on T are shallower, whatever the tags
+	want := map[string]string{"X": "string", "Y": "bool"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,237 @@
+package reveal
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func (v *EndpointsVisitor) foldIntConstant(expr ast.Expr, pkg *packages.Package) (int, bool) {
+	return foldedInt(v.foldConstant(expr, pkg, map[types.Object]bool{}))
+}
+
+func foldedInt(value constant.Value, ok bool) (int, bool) {
+	if !ok || value.Kind() != constant.Int {
+		return 0, false
+	}
+
+	folded, ok := constant.Int64Val(value)
+	if !ok {
+		return 0, false
+	}
+
+	return int(folded), true
+}
+
+func (v *EndpointsVisitor) foldStringConstant(expr ast.Expr, pkg *packages.Package) (string, bool) {
+	value, ok := v.foldConstant(expr, pkg, map[types.Object]bool{})
+	if !ok || value.Kind() != constant.String {
+		return "", false
+	}
+
+	folded := constant.StringVal(value)
+	if len(folded) == 0 {
+		return "", false
+	}
+
+	return folded, true
+}
+
+// foldStringSlice folds a composite literal of string constants, e.g. the
+// methods passed to RouterGroup.Match. The boolean is false if any of the
+// elements could not be folded.
+func (v *EndpointsVisitor) foldStringSlice(expr ast.Expr, pkg *packages.Package, seen map[types.Object]bool) ([]string, bool) {
+	if ident, ok := expr.(*ast.Ident); ok {
+		expr, pkg = v.resolveExpr(ident, pkg)
+	}
+
+	lit, ok := unparen(expr).(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+
+	out := make([]string, 0, len(lit.Elts))
+	all := true
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+		}
+		if value, ok := v.foldConstant(elt, pkg, seen); ok && value.Kind() == constant.String {
+			out = append(out, constant.StringVal(value))
+		} else {
+			all = false
+		}
+	}
+	return out, all
+}
+
+// foldConstant evaluates expr when it is known at compile time, or when it can
+// be computed from the values bound so far: identifiers and struct fields are
+// followed to their bindings (including the arguments of followed calls), and
+// string concatenations and pure string-building calls are evaluated. The
+// bindings being followed are in seen, so that a variable bound in terms of
+// itself (e.g. prefix = prefix + "/v2" when the previous value of prefix is
+// unknown) does not fold.
+func (v *EndpointsVisitor) foldConstant(expr ast.Expr, pkg *packages.Package, seen map[types.Object]bool) (constant.Value, bool) {
+	if expr == nil {
+		return nil, false
+	}
+
+	if ty, ok := pkg.TypesInfo.Types[expr]; ok && ty.Value != nil {
+		return ty.Value, true
+	}
+
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return v.foldConstant(e.X, pkg, seen)
+
+	case *ast.Ident:
+		if resolved, rpkg := v.resolveExpr(e, pkg); resolved != e {
+			return v.foldBinding(pkg.TypesInfo.ObjectOf(e), resolved, rpkg, seen)
+		}
+
+	case *ast.SelectorExpr:
+		if resolved, rpkg := v.resolveSelector(e, pkg); resolved != nil {
+			return v.foldBinding(pkg.TypesInfo.ObjectOf(e.Sel), resolved, rpkg, seen)
+		}
+
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			if x, ok := v.foldConstant(e.X, pkg, seen); ok && x.Kind() == constant.String {
+				if y, ok := v.foldConstant(e.Y, pkg, seen); ok && y.Kind() == constant.String {
+					return constant.BinaryOp(x, token.ADD, y), true
+				}
+			}
+		}
+
+	case *ast.CallExpr:
+		if folded, ok := v.foldCall(e, pkg, seen); ok {
+			return constant.MakeString(folded), true
+		}
+	}
+
+	return nil, false
+}
+
+// foldBinding folds the expression obj is bound to, unless obj is already
+// being folded.
+func (v *EndpointsVisitor) foldBinding(obj types.Object, expr ast.Expr, pkg *packages.Package, seen map[types.Object]bool) (constant.Value, bool) {
+	if obj == nil || seen[obj] {
+		return nil, false
+	}
+	seen[obj] = true
+	defer delete(seen, obj)
+
+	return v.foldConstant(expr, pkg, seen)
+}
+
+// foldCall evaluates calls to well-known pure functions building strings.
+func (v *EndpointsVisitor) foldCall(callexpr *ast.CallExpr, pkg *packages.Package, seen map[types.Object]bool) (string, bool) {
+	fn := calledFunc(callexpr, pkg)
+	if fn == nil {
+		return "", false
+	}
+
+	switch fn.FullName() {
+	case "path.Join":
+		args, ok := v.foldStringArgs(callexpr, pkg, seen)
+		if !ok {
+			return "", false
+		}
+		return path.Join(args...), true
+
+	case "strings.Join":
+		if len(callexpr.Args) != 2 {
+			return "", false
+		}
+		elems, ok := v.foldStringSlice(callexpr.Args[0], pkg, seen)
+		if !ok {
+			return "", false
+		}
+		sep, ok := v.foldConstant(callexpr.Args[1], pkg, seen)
+		if !ok || sep.Kind() != constant.String {
+			return "", false
+		}
+		return strings.Join(elems, constant.StringVal(sep)), true
+
+	case "fmt.Sprint", "fmt.Sprintf":
+		if callexpr.Ellipsis.IsValid() {
+			return "", false
+		}
+		args := make([]interface{}, 0, len(callexpr.Args))
+		for _, arg := range callexpr.Args {
+			value, ok := v.foldConstant(arg, pkg, seen)
+			if !ok {
+				return "", false
+			}
+			args = append(args, constantToInterface(value))
+		}
+		if fn.Name() == "Sprint" {
+			return fmt.Sprint(args...), true
+		}
+		if len(args) == 0 {
+			return "", false
+		}
+		format, ok := args[0].(string)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf(format, args[1:]...), true
+
+	case "strconv.Itoa":
+		if len(callexpr.Args) != 1 {
+			return "", false
+		}
+		if i, ok := foldedInt(v.foldConstant(callexpr.Args[0], pkg, seen)); ok {
+			return strconv.Itoa(i), true
+		}
+	}
+
+	return "", false
+}
+
+// foldStringArgs folds the arguments of a variadic string function, whether
+// they are passed one by one or spread from a slice literal.
+func (v *EndpointsVisitor) foldStringArgs(callexpr *ast.CallExpr, pkg *packages.Package, seen map[types.Object]bool) ([]string, bool) {
+	if callexpr.Ellipsis.IsValid() {
+		if len(callexpr.Args) != 1 {
+			return nil, false
+		}
+		return v.foldStringSlice(callexpr.Args[0], pkg, seen)
+	}
+
+	out := make([]string, 0, len(callexpr.Args))
+	for _, arg := range callexpr.Args {
+		value, ok := v.foldConstant(arg, pkg, seen)
+		if !ok || value.Kind() != constant.String {
+			return nil, false
+		}
+		out = append(out, constant.StringVal(value))
+	}
+	return out, true
+}
+
+func constantToInterface(value constant.Value) interface{} {
+	switch value.Kind() {
+	case constant.String:
+		return constant.StringVal(value)
+	case constant.Bool:
+		return constant.BoolVal(value)
+	case constant.Int:
+		if i, ok := constant.Int64Val(value); ok {
+			return i
+		}
+	case constant.Float:
+		if f, ok := constant.Float64Val(value); ok {
+			return f
+		}
+	}
+	return value.ExactString()
+}
@@ -0,0 +1,131 @@
+package reveal
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// marshalers are the functions a MarshalJSON method may delegate to, whose
+// first argument is what ends up written.
+var marshalers = map[string]bool{
+	"encoding/json.Marshal":       true,
+	"encoding/json.MarshalIndent": true,
+}
+
+// marshaledType infers the type a MarshalJSON method writes out of its body,
+// e.g. T for `return json.Marshal(T(x))`. It reports a diagnostic and returns
+// nil when the returns do not agree on a single type.
+func (v *EndpointsVisitor) marshaledType(method *types.Func) types.Type {
+	decl, pkg := v.resolveFuncDecl(method)
+	if decl == nil || decl.Body == nil {
+		v.reportMarshaler(method, nil)
+		return nil
+	}
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		v.recordBinding(n, pkg)
+		return true
+	})
+
+	var marshaled types.Type
+	ok := true
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if _, isFunc := n.(*ast.FuncLit); isFunc || !ok {
+			return false
+		}
+
+		ret, isReturn := n.(*ast.ReturnStmt)
+		if !isReturn || len(ret.Results) == 0 {
+			return true
+		}
+
+		result := unparen(ret.Results[0])
+		if ident, isIdent := result.(*ast.Ident); isIdent {
+			if ident.Name == "nil" {
+				return false
+			}
+			result, _ = v.resolveExpr(ident, pkg)
+		}
+
+		ty := v.marshaledArg(result, pkg)
+		switch {
+		case ty == nil:
+			ok = false
+		case marshaled == nil:
+			marshaled = ty
+		case !types.Identical(marshaled, ty):
+			ok = false
+		}
+		return false
+	})
+
+	if !ok || marshaled == nil {
+		v.reportMarshaler(method, pkg)
+		return nil
+	}
+
+	// a type declared within the method only serves to drop the methods of
+	// the receiver, what matters is its structure
+	if named, isNamed := marshaled.(*types.Named); isNamed {
+		if obj := named.Obj(); obj.Pkg() != nil && obj.Parent() != obj.Pkg().Scope() {
+			marshaled = named.Underlying()
+		}
+	}
+
+	if recv := method.Type().(*types.Signature).Recv(); recv != nil && types.Identical(flattenPointers(marshaled), flattenPointers(recv.Type())) {
+		v.reportMarshaler(method, pkg)
+		return nil
+	}
+
+	return marshaled
+}
+
+// marshaledArg returns the type of what call marshals when it is a call to a
+// known marshaler.
+func (v *EndpointsVisitor) marshaledArg(expr ast.Expr, pkg *packages.Package) types.Type {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil
+	}
+
+	fn := calledFunc(call, pkg)
+	if fn == nil || !marshalers[fn.FullName()] {
+		return nil
+	}
+
+	return v.typeOf(call.Args[0], pkg)
+}
+
+// reportMarshaler reports the MarshalJSON methods whose output is unknown,
+// once each.
+func (v *EndpointsVisitor) reportMarshaler(method *types.Func, pkg *packages.Package) {
+	if v.reported[method] {
+		return
+	}
+	v.reported[method] = true
+
+	d := Diagnostic{Message: fmt.Sprintf("cannot infer what %s marshals, its schema is left untyped: override it in the config", typeName(method))}
+	if pkg == nil && len(v.entrypoints) > 0 {
+		pkg = v.entrypoints[0] // packages share their file set
+	}
+	if pkg != nil {
+		d.Pos = pkg.Fset.Position(method.Pos())
+	}
+	v.diagnostics = append(v.diagnostics, d)
+}
+
+// typeName is the qualified name of the receiver of method, as overrides are
+// indexed.
+func typeName(method *types.Func) string {
+	recv := method.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return method.FullName()
+	}
+	if named, ok := flattenPointers(recv.Type()).(*types.Named); ok && named.Obj().Pkg() != nil {
+		return named.Obj().Pkg().Path() + "." + named.Obj().Name()
+	}
+	return recv.Type().String()
+}
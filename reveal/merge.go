@@ -0,0 +1,160 @@
+package reveal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// mergeParams merges the parameters sharing the same location and name, as
+// several sources (the path, groups, handlers...) may declare the same one.
+// Declarations that cannot be reconciled are reported as conflicts, the first
+// one winning.
+func mergeParams(params openapi3.Parameters) (openapi3.Parameters, []string) {
+	var out openapi3.Parameters
+	var conflicts []string
+
+	index := map[[2]string]int{}
+	for _, param := range params {
+		if param == nil || param.Value == nil {
+			out = append(out, param)
+			continue
+		}
+
+		key := [2]string{param.Value.In, param.Value.Name}
+		if key[0] == openapi3.ParameterInHeader {
+			// header names are case insensitive
+			key[1] = http.CanonicalHeaderKey(key[1])
+		}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(out)
+			out = append(out, param)
+			continue
+		}
+
+		merged, conflict := mergeParam(out[i].Value, param.Value)
+		out[i] = &openapi3.ParameterRef{Value: merged}
+		if conflict != "" {
+			conflicts = append(conflicts, fmt.Sprintf("conflicting declarations of %s parameter %q: %s", key[0], key[1], conflict))
+		}
+	}
+
+	return out, conflicts
+}
+
+// mergeParam merges two declarations of the same parameter into a new one.
+func mergeParam(a, b *openapi3.Parameter) (*openapi3.Parameter, string) {
+	merged := *a
+	merged.Required = a.Required || b.Required
+	if merged.Description == "" {
+		merged.Description = b.Description
+	}
+	if merged.Style == "" {
+		merged.Style, merged.Explode = b.Style, b.Explode
+	}
+
+	schema, conflict := mergeSchemas(a.Schema, b.Schema)
+	merged.Schema = schema
+
+	return &merged, conflict
+}
+
+// mergeSchemas combines what two schemas of the same value tell. Untyped
+// strings are what any parameter is read as, so they give way to any other
+// type.
+func mergeSchemas(a, b *openapi3.SchemaRef) (*openapi3.SchemaRef, string) {
+	switch {
+	case a == nil:
+		return b, ""
+	case b == nil:
+		return a, ""
+	case a.Ref != "" || b.Ref != "":
+		if a.Ref == b.Ref {
+			return a, ""
+		}
+		return a, fmt.Sprintf("%s and %s", describeSchema(a), describeSchema(b))
+	case a.Value == nil:
+		return b, ""
+	case b.Value == nil:
+		return a, ""
+	}
+
+	if isLooseString(a.Value) && b.Value.Type != openapi3.TypeString {
+		return &openapi3.SchemaRef{Value: withDefault(b.Value, a.Value.Default)}, ""
+	}
+	if isLooseString(b.Value) && a.Value.Type != openapi3.TypeString {
+		return a, ""
+	}
+
+	am, err := schemaMap(a.Value)
+	if err != nil {
+		return a, ""
+	}
+	bm, err := schemaMap(b.Value)
+	if err != nil {
+		return a, ""
+	}
+
+	var conflicting []string
+	for key, value := range bm {
+		if prev, ok := am[key]; !ok {
+			am[key] = value
+		} else if !reflect.DeepEqual(prev, value) {
+			conflicting = append(conflicting, key)
+		}
+	}
+
+	var merged openapi3.Schema
+	if data, err := json.Marshal(am); err != nil || json.Unmarshal(data, &merged) != nil {
+		return a, ""
+	}
+
+	if len(conflicting) > 0 {
+		sort.Strings(conflicting)
+		return &openapi3.SchemaRef{Value: &merged}, fmt.Sprintf("%s and %s differ on %s", describeSchema(a), describeSchema(b), strings.Join(conflicting, ", "))
+	}
+	return &openapi3.SchemaRef{Value: &merged}, ""
+}
+
+// isLooseString reports whether schema only tells a value is a string, as all
+// parameters are before being parsed.
+func isLooseString(schema *openapi3.Schema) bool {
+	if schema.Type != openapi3.TypeString {
+		return false
+	}
+	m, err := schemaMap(schema)
+	if err != nil {
+		return false
+	}
+	delete(m, "type")
+	delete(m, "default")
+	return len(m) == 0
+}
+
+func schemaMap(schema *openapi3.Schema) (map[string]interface{}, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
+
+func describeSchema(schema *openapi3.SchemaRef) string {
+	switch {
+	case schema.Ref != "":
+		return schema.Ref
+	case schema.Value.Format != "":
+		return schema.Value.Type + "/" + schema.Value.Format
+	case schema.Value.Type != "":
+		return schema.Value.Type
+	}
+	return "untyped"
+}
@@ -0,0 +1,134 @@
+package reveal
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestMergeParams(t *testing.T) {
+	query := func(name string, schema *openapi3.Schema) *openapi3.ParameterRef {
+		return newParam(openapi3.ParameterInQuery, name, schema)
+	}
+	required := func(param *openapi3.ParameterRef) *openapi3.ParameterRef {
+		param.Value.Required = true
+		return param
+	}
+	referenced := func(param *openapi3.ParameterRef, ref string) *openapi3.ParameterRef {
+		param.Value.Schema = openapi3.NewSchemaRef(ref, nil)
+		return param
+	}
+	described := func(param *openapi3.ParameterRef, description string) *openapi3.ParameterRef {
+		param.Value.Description = description
+		return param
+	}
+
+	tests := []struct {
+		name      string
+		params    openapi3.Parameters
+		want      string // JSON of the merged parameters
+		conflicts []string
+	}{
+		{
+			name: "distinct parameters are kept",
+			params: openapi3.Parameters{
+				query("a", openapi3.NewStringSchema()),
+				newParam(openapi3.ParameterInHeader, "a", openapi3.NewStringSchema()),
+			},
+			want: `[{"in":"query","name":"a","schema":{"type":"string"}},{"in":"header","name":"a","schema":{"type":"string"}}]`,
+		},
+		{
+			name: "header names are case insensitive",
+			params: openapi3.Parameters{
+				newParam(openapi3.ParameterInHeader, "X-Request-ID", openapi3.NewStringSchema()),
+				required(newParam(openapi3.ParameterInHeader, "x-request-id", openapi3.NewStringSchema())),
+			},
+			want: `[{"in":"header","name":"X-Request-ID","required":true,"schema":{"type":"string"}}]`,
+		},
+		{
+			name: "query names are case sensitive",
+			params: openapi3.Parameters{
+				query("id", openapi3.NewStringSchema()),
+				query("ID", openapi3.NewStringSchema()),
+			},
+			want: `[{"in":"query","name":"id","schema":{"type":"string"}},{"in":"query","name":"ID","schema":{"type":"string"}}]`,
+		},
+		{
+			name: "loose strings give way to typed schemas",
+			params: openapi3.Parameters{
+				query("page", openapi3.NewStringSchema()),
+				query("page", openapi3.NewIntegerSchema()),
+			},
+			want: `[{"in":"query","name":"page","schema":{"type":"integer"}}]`,
+		},
+		{
+			name: "defaults of loose strings are converted",
+			params: openapi3.Parameters{
+				query("page", withDefault(openapi3.NewStringSchema(), "1")),
+				query("page", openapi3.NewIntegerSchema()),
+			},
+			want: `[{"in":"query","name":"page","schema":{"default":1,"type":"integer"}}]`,
+		},
+		{
+			name: "typed schemas are kept over loose strings",
+			params: openapi3.Parameters{
+				query("page", openapi3.NewIntegerSchema()),
+				query("page", openapi3.NewStringSchema()),
+			},
+			want: `[{"in":"query","name":"page","schema":{"type":"integer"}}]`,
+		},
+		{
+			name: "constraints are combined",
+			params: openapi3.Parameters{
+				query("page", openapi3.NewIntegerSchema().WithMin(1)),
+				query("page", openapi3.NewIntegerSchema().WithMax(10)),
+			},
+			want: `[{"in":"query","name":"page","schema":{"maximum":10,"minimum":1,"type":"integer"}}]`,
+		},
+		{
+			name: "required wins and the first description is kept",
+			params: openapi3.Parameters{
+				described(query("q", openapi3.NewStringSchema()), "first"),
+				described(required(query("q", openapi3.NewStringSchema())), "second"),
+			},
+			want: `[{"description":"first","in":"query","name":"q","required":true,"schema":{"type":"string"}}]`,
+		},
+		{
+			name: "conflicting types are reported, the first winning",
+			params: openapi3.Parameters{
+				query("force", openapi3.NewBoolSchema()),
+				query("force", openapi3.NewIntegerSchema()),
+			},
+			want:      `[{"in":"query","name":"force","schema":{"type":"boolean"}}]`,
+			conflicts: []string{`conflicting declarations of query parameter "force": boolean and integer differ on type`},
+		},
+		{
+			name: "conflicting references are reported",
+			params: openapi3.Parameters{
+				referenced(query("s", openapi3.NewStringSchema()), "#/components/schemas/A"),
+				referenced(query("s", openapi3.NewStringSchema()), "#/components/schemas/B"),
+			},
+			want:      `[{"in":"query","name":"s","schema":{"$ref":"#/components/schemas/A"}}]`,
+			conflicts: []string{`conflicting declarations of query parameter "s": #/components/schemas/A and #/components/schemas/B`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, conflicts := mergeParams(test.params)
+
+			got, err := json.Marshal(merged)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+			if !reflect.DeepEqual(conflicts, test.conflicts) {
+				t.Errorf("got conflicts %q, want %q", conflicts, test.conflicts)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package reveal
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+	"time"
+
+	"github.com/fatih/structtag"
+	"github.com/getkin/kin-openapi/openapi3"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// typedParamsFromStructFields documents the fields of the struct bound by gin
+// with the given tag, typing them after the Go fields and their binding rules.
+func (v *EndpointsVisitor) typedParamsFromStructFields(ty types.Type, tag string, in string) openapi3.Parameters {
+	var out openapi3.Parameters
+
+	v.eachTaggedField(ty, tag, func(field *types.Var, tags *structtag.Tags, name string) {
+		out = append(out, v.fieldParam(field, tags, tag, name, in))
+	})
+
+	return out
+}
+
+// eachTaggedField calls fn for each exported field of the struct ty that gin
+// binds with the given tag, along with the name it is bound to. Like gin's
+// mapForm, the fields of embedded and nested structs are bound as if they
+// were fields of ty.
+func (v *EndpointsVisitor) eachTaggedField(ty types.Type, tag string, fn func(field *types.Var, tags *structtag.Tags, name string)) {
+	v.eachNestedTaggedField(ty, tag, fn, map[types.Type]bool{})
+}
+
+func (v *EndpointsVisitor) eachNestedTaggedField(ty types.Type, tag string, fn func(field *types.Var, tags *structtag.Tags, name string), seen map[types.Type]bool) {
+	if ty == nil || seen[ty] {
+		return
+	}
+	seen[ty] = true
+	defer delete(seen, ty)
+
+	strct, ok := flattenPointers(ty).Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	for i, max := 0, strct.NumFields(); i < max; i++ {
+		f := strct.Field(i)
+		if !f.Exported() && !f.Embedded() {
+			continue
+		}
+
+		tags, err := structtag.Parse(strct.Tag(i))
+		if err != nil {
+			continue
+		}
+
+		// like gin, fall back on the field name when it is not tagged
+		name := f.Name()
+		if value, err := tags.Get(tag); err == nil {
+			name = value.Name
+		}
+		if name == "-" {
+			continue
+		}
+
+		if isBoundStruct(f.Type()) {
+			v.eachNestedTaggedField(f.Type(), tag, fn, seen)
+			continue
+		}
+
+		if f.Exported() {
+			fn(f, tags, name)
+		}
+	}
+}
+
+// isBoundStruct reports whether gin binds the fields of a struct of type ty
+// rather than the struct as a whole.
+func isBoundStruct(ty types.Type) bool {
+	ty = flattenPointers(ty)
+	if _, ok := ty.Underlying().(*types.Struct); !ok {
+		return false
+	}
+	return !isWellKnown(ty)
+}
+
+// fieldSchema returns the schema of a struct field bound with the given tag,
+// constrained by its binding rules, and whether it is required.
+func (v *EndpointsVisitor) fieldSchema(field *types.Var, tags *structtag.Tags, tag string) (*openapi3.SchemaRef, bool) {
+	schema := v.schemas.ToSchemaRef(field.Type(), "json")
+
+	if format, err := tags.Get("time_format"); err == nil && flattenPointers(field.Type()).String() == "time.Time" {
+		schema = &openapi3.SchemaRef{Value: timeFormatSchema(format.Value())}
+	}
+
+	if value, err := tags.Get(tag); err == nil && schema != nil && schema.Value != nil {
+		for _, option := range value.Options {
+			if strings.HasPrefix(option, "default=") {
+				schema = &openapi3.SchemaRef{Value: withDefault(schema.Value, strings.TrimPrefix(option, "default="))}
+			}
+		}
+	}
+
+	var required bool
+	if binding, err := tags.Get("binding"); err == nil {
+		schema, required = applyBindingTag(schema, field.Type(), binding.Value())
+	}
+
+	return schema, required
+}
+
+// timeFormatSchema documents a time bound with the given time_format layout.
+func timeFormatSchema(layout string) *openapi3.Schema {
+	switch layout {
+	case "unix", "unixnano":
+		return openapi3.NewInt64Schema()
+	case time.RFC3339, time.RFC3339Nano:
+		return openapi3.NewDateTimeSchema()
+	case "2006-01-02":
+		return openapi3.NewStringSchema().WithFormat("date")
+	}
+
+	schema := openapi3.NewStringSchema()
+	schema.Description = fmt.Sprintf("time formatted as %s", layout)
+	return schema
+}
+
+// fieldParam documents a struct field bound to a parameter.
+func (v *EndpointsVisitor) fieldParam(field *types.Var, tags *structtag.Tags, tag string, name string, in string) *openapi3.ParameterRef {
+	schema, required := v.fieldSchema(field, tags, tag)
+
+	param := &openapi3.Parameter{
+		In:          in,
+		Name:        name,
+		Description: v.fieldDoc(field),
+		Required:    required,
+		Schema:      schema,
+	}
+	if in == openapi3.ParameterInQuery {
+		setQueryStyle(param, field.Type())
+	}
+
+	return &openapi3.ParameterRef{Value: param}
+}
+
+// queryParam documents a query parameter read directly from the request.
+func queryParam(name string, schema *openapi3.Schema) *openapi3.ParameterRef {
+	ref := newParam(openapi3.ParameterInQuery, name, schema)
+	param := ref.Value
+
+	switch schema.Type {
+	case openapi3.TypeArray:
+		param.Style = openapi3.SerializationForm
+		param.Explode = openapi3.BoolPtr(true)
+	case openapi3.TypeObject:
+		param.Style = openapi3.SerializationDeepObject
+		param.Explode = openapi3.BoolPtr(true)
+	}
+
+	return ref
+}
+
+// newParam documents a parameter read directly from the request.
+func newParam(in string, name string, schema *openapi3.Schema) *openapi3.ParameterRef {
+	return &openapi3.ParameterRef{
+		Value: &openapi3.Parameter{
+			In:     in,
+			Name:   name,
+			Schema: &openapi3.SchemaRef{Value: schema},
+		},
+	}
+}
+
+// setQueryStyle documents how gin expects slices (?tag=a&tag=b) and maps
+// (?meta[key]=value) in the query.
+func setQueryStyle(param *openapi3.Parameter, ty types.Type) {
+	switch flattenPointers(ty).Underlying().(type) {
+	case *types.Slice, *types.Array:
+		param.Style = openapi3.SerializationForm
+		param.Explode = openapi3.BoolPtr(true)
+	case *types.Map:
+		param.Style = openapi3.SerializationDeepObject
+		param.Explode = openapi3.BoolPtr(true)
+	}
+}
+
+// fieldDoc returns the doc (or line) comment of a struct field.
+func (v *EndpointsVisitor) fieldDoc(field *types.Var) string {
+	if field.Pkg() == nil {
+		return ""
+	}
+
+	pkg := v.pkgsByID[field.Pkg().Path()]
+	if pkg == nil {
+		return ""
+	}
+
+	for _, file := range pkg.Syntax {
+		if field.Pos() < file.Pos() || field.Pos() >= file.End() {
+			continue
+		}
+
+		path, _ := astutil.PathEnclosingInterval(file, field.Pos(), field.Pos())
+		for _, node := range path {
+			if f, ok := node.(*ast.Field); ok {
+				if f.Doc != nil {
+					return strings.TrimSpace(f.Doc.Text())
+				}
+				if f.Comment != nil {
+					return strings.TrimSpace(f.Comment.Text())
+				}
+				return ""
+			}
+		}
+	}
+
+	return ""
+}
+
+// refinePathParams refines the path parameters inferred from the route with
+// what is known from the uri bindings of its handlers, matching them by name.
+func refinePathParams(params openapi3.Parameters, uriParams openapi3.Parameters) openapi3.Parameters {
+	if len(uriParams) == 0 {
+		return params
+	}
+
+	out := make(openapi3.Parameters, 0, len(params))
+	for _, param := range params {
+		if param.Value != nil && param.Value.In == openapi3.ParameterInPath {
+			for _, uri := range uriParams {
+				if uri.Value.Name != param.Value.Name {
+					continue
+				}
+
+				// group parameters are shared with the other endpoints
+				refined := *param.Value
+				refined.Schema = uri.Value.Schema
+				if uri.Value.Description != "" {
+					refined.Description = uri.Value.Description
+				}
+				param = &openapi3.ParameterRef{Value: &refined}
+			}
+		}
+		out = append(out, param)
+	}
+
+	return out
+}
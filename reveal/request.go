@@ -0,0 +1,179 @@
+package reveal
+
+import (
+	"go/ast"
+	"go/types"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"golang.org/x/tools/go/packages"
+)
+
+// inspectRequest infers parameters read through the raw *http.Request of the
+// gin context (c.Request.Header.Get, c.Request.URL.Query().Get...).
+func (v *EndpointsVisitor) inspectRequest(callexpr *ast.CallExpr, pkg *packages.Package, info *handlerInfo) {
+	sel, ok := callexpr.Fun.(*ast.SelectorExpr)
+	if !ok || len(callexpr.Args) == 0 {
+		return
+	}
+
+	name, ok := v.foldStringConstant(callexpr.Args[0], pkg)
+	if !ok {
+		return
+	}
+
+	recv := pkg.TypesInfo.TypeOf(sel.X)
+	switch {
+	case isHTTPRequest(recv):
+		switch sel.Sel.Name {
+		case "Cookie":
+			info.params = append(info.params, newParam(openapi3.ParameterInCookie, name, openapi3.NewStringSchema()))
+		case "FormValue":
+			info.params = append(info.params, queryParam(name, openapi3.NewStringSchema()))
+		case "PostFormValue":
+			info.addFormField(name, &openapi3.SchemaRef{Value: openapi3.NewStringSchema()}, false)
+		case "FormFile":
+			info.addFormField(name, &openapi3.SchemaRef{Value: openapi3.NewStringSchema().WithFormat("binary")}, false)
+		}
+
+	case isNamed(recv, "net/http", "Header"):
+		if !v.isRequestField(sel.X, "Header", pkg) {
+			return
+		}
+		switch sel.Sel.Name {
+		case "Get":
+			info.params = append(info.params, newParam(openapi3.ParameterInHeader, name, openapi3.NewStringSchema()))
+		case "Values":
+			schema := openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema())
+			info.params = append(info.params, newParam(openapi3.ParameterInHeader, name, schema))
+		}
+
+	case isNamed(recv, "net/url", "Values"):
+		if !v.isRequestQuery(sel.X, pkg) {
+			return
+		}
+		switch sel.Sel.Name {
+		case "Get":
+			info.params = append(info.params, queryParam(name, openapi3.NewStringSchema()))
+		}
+	}
+}
+
+// isRequestField reports whether expr is the given field of an *http.Request,
+// possibly through variables.
+func (v *EndpointsVisitor) isRequestField(expr ast.Expr, field string, pkg *packages.Package) bool {
+	expr, pkg = v.resolve(expr, pkg)
+
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		return e.Sel.Name == field && isHTTPRequest(pkg.TypesInfo.TypeOf(e.X))
+	case *ast.Ident:
+		// variables set to a struct field resolve to the field itself
+		obj, ok := pkg.TypesInfo.ObjectOf(e).(*types.Var)
+		return ok && obj.IsField() && obj.Name() == field && obj.Pkg() != nil && obj.Pkg().Path() == "net/http"
+	}
+	return false
+}
+
+// isRequestQuery reports whether expr is the query of an *http.Request, as
+// returned by c.Request.URL.Query().
+func (v *EndpointsVisitor) isRequestQuery(expr ast.Expr, pkg *packages.Package) bool {
+	expr, pkg = v.resolve(expr, pkg)
+
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Query" && v.isRequestField(sel.X, "URL", pkg)
+}
+
+// resolve follows the variables expr may refer to.
+func (v *EndpointsVisitor) resolve(expr ast.Expr, pkg *packages.Package) (ast.Expr, *packages.Package) {
+	expr = unparen(expr)
+	if ident, ok := expr.(*ast.Ident); ok {
+		resolved, rpkg := v.resolveExpr(ident, pkg)
+		return unparen(resolved), rpkg
+	}
+	return expr, pkg
+}
+
+func isHTTPRequest(ty types.Type) bool {
+	return ty != nil && ty.String() == "*net/http.Request"
+}
+
+// isNamed reports whether ty is the named type pkgPath.name.
+func isNamed(ty types.Type, pkgPath, name string) bool {
+	named, ok := ty.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == name
+}
+
+// decoders are the packages decoding request bodies, indexed by path, along
+// with the content type they expect and the struct tag they read.
+var decoders = map[string]struct {
+	contentType string
+	tag         string
+}{
+	"encoding/json": {"application/json", "json"},
+	"encoding/xml":  {"application/xml", "xml"},
+}
+
+// inspectBody infers the request body of handlers reading it without gin
+// binders, e.g. json.NewDecoder(c.Request.Body).Decode(&req).
+func (v *EndpointsVisitor) inspectBody(callexpr *ast.CallExpr, pkg *packages.Package, info *handlerInfo) {
+	fn := calledFunc(callexpr, pkg)
+	if fn == nil || fn.Pkg() == nil {
+		return
+	}
+
+	switch fn.FullName() {
+	case "io.ReadAll", "io/ioutil.ReadAll":
+		if len(callexpr.Args) > 0 && v.isRequestField(callexpr.Args[0], "Body", pkg) {
+			info.rawBody = true
+		}
+
+	case "encoding/json.Unmarshal", "encoding/xml.Unmarshal":
+		// the data must have been read from the request
+		if len(callexpr.Args) > 1 && v.isRawBody(callexpr.Args[0], pkg) {
+			decoder := decoders[fn.Pkg().Path()]
+			info.addRequestBody(decoder.contentType, v.schemas.ToSchemaRef(v.typeOf(callexpr.Args[1], pkg), decoder.tag))
+		}
+
+	case "(*encoding/json.Decoder).Decode", "(*encoding/xml.Decoder).Decode":
+		sel, ok := callexpr.Fun.(*ast.SelectorExpr)
+		if !ok || len(callexpr.Args) == 0 {
+			return
+		}
+		x, xpkg := v.resolve(sel.X, pkg)
+		newDecoder, ok := x.(*ast.CallExpr)
+		if !ok || len(newDecoder.Args) == 0 || !v.isRequestField(newDecoder.Args[0], "Body", xpkg) {
+			return
+		}
+		decoder := decoders[fn.Pkg().Path()]
+		info.addRequestBody(decoder.contentType, v.schemas.ToSchemaRef(v.typeOf(callexpr.Args[0], pkg), decoder.tag))
+	}
+}
+
+// isRawBody reports whether expr holds the raw request body, as returned by
+// c.GetRawData() or io.ReadAll(c.Request.Body).
+func (v *EndpointsVisitor) isRawBody(expr ast.Expr, pkg *packages.Package) bool {
+	expr, pkg = v.resolve(expr, pkg)
+
+	callexpr, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	if sel, ok := callexpr.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "GetRawData" && isGinContext(pkg.TypesInfo.TypeOf(sel.X)) {
+		return true
+	}
+
+	fn := calledFunc(callexpr, pkg)
+	if fn == nil {
+		return false
+	}
+	switch fn.FullName() {
+	case "io.ReadAll", "io/ioutil.ReadAll":
+		return len(callexpr.Args) > 0 && v.isRequestField(callexpr.Args[0], "Body", pkg)
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package reveal
 import (
 	"context"
 	"fmt"
+	"go/token"
 	"net/http"
 	"os"
 	"path"
@@ -18,7 +19,111 @@ import (
 
 var githubRegexp = regexp.MustCompilePOSIX(`^git@github\.com:([^/]+/[^.]+)\.git$`)
 
+// Config tunes how reveal analyzes a codebase.
+type Config struct {
+	// Entrypoints are the packages to start the analysis from, either as
+	// import paths or as directories relative to the analyzed one (e.g.
+	// "./cmd/api"). They default to the package of the analyzed directory.
+	Entrypoints []string
+
+	// Report is called with whatever could not be documented faithfully,
+	// e.g. conflicting declarations of the same parameter.
+	Report func(Diagnostic)
+
+	// Schemas override the schemas of the types indexed by qualified name
+	// (e.g. github.com/shopspring/decimal.Decimal), for when they marshal
+	// themselves in ways that cannot be inferred.
+	Schemas map[string]*openapi3.Schema
+}
+
+// Diagnostic is a problem met while documenting a codebase.
+type Diagnostic struct {
+	Pos     token.Position // zero when unknown
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Pos.IsValid() {
+		return fmt.Sprintf("%s: %s", d.Pos, d.Message)
+	}
+	return d.Message
+}
+
+// Reveal generates the OpenAPI schema of the package found in dir.
 func Reveal(ctx context.Context, dir string) (*openapi3.T, error) {
+	return RevealConfig(ctx, dir, Config{})
+}
+
+// RevealConfig generates a single OpenAPI schema out of the entrypoints of cfg,
+// merging the endpoints they register.
+func RevealConfig(ctx context.Context, dir string, cfg Config) (*openapi3.T, error) {
+	p, err := loadProject(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entrypoints []*packages.Package
+	if len(cfg.Entrypoints) == 0 {
+		pkg := p.pkgByDir(p.absDir)
+		if pkg == nil {
+			return nil, fmt.Errorf("no package found in %s", p.absDir)
+		}
+		entrypoints = append(entrypoints, pkg)
+	}
+	for _, name := range cfg.Entrypoints {
+		pkg := p.pkgByName(name)
+		if pkg == nil {
+			return nil, fmt.Errorf("entrypoint %s not found in %s", name, p.absDir)
+		}
+		entrypoints = append(entrypoints, pkg)
+	}
+
+	return p.document(p.title(p.absDir), p.absDir, entrypoints, cfg), nil
+}
+
+// RevealAll generates one OpenAPI schema per binary, indexed and titled by
+// package path: each of the entrypoints of cfg, or every main package found
+// under dir if there are none.
+func RevealAll(ctx context.Context, dir string, cfg Config) (map[string]*openapi3.T, error) {
+	p, err := loadProject(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entrypoints []*packages.Package
+	if len(cfg.Entrypoints) == 0 {
+		for _, pkg := range p.pkgs {
+			if pkg.Name == "main" {
+				entrypoints = append(entrypoints, pkg)
+			}
+		}
+	}
+	for _, name := range cfg.Entrypoints {
+		pkg := p.pkgByName(name)
+		if pkg == nil {
+			return nil, fmt.Errorf("entrypoint %s not found in %s", name, p.absDir)
+		}
+		entrypoints = append(entrypoints, pkg)
+	}
+
+	out := map[string]*openapi3.T{}
+	for _, pkg := range entrypoints {
+		out[pkg.PkgPath] = p.document(pkg.PkgPath, pkgDir(pkg), []*packages.Package{pkg}, cfg)
+	}
+
+	return out, nil
+}
+
+// project is a loaded codebase along with its git infos.
+type project struct {
+	absDir         string
+	gitRoot        string
+	githubUserRepo string
+	gitHash        string
+	pkgs           []*packages.Package
+}
+
+func loadProject(ctx context.Context, dir string) (*project, error) {
 	// Resolve the root path to the directory
 
 	dir, err := homedir.Expand(dir)
@@ -34,65 +139,112 @@ func Reveal(ctx context.Context, dir string) (*openapi3.T, error) {
 		dir = path.Join(wd, dir)
 	}
 
-	absDir := path.Clean(dir)
+	p := &project{absDir: path.Clean(dir)}
 
 	// Try to acquire git infos
 
-	var gitRoot, githubUserRepo, gitHash string
-
-	if repo, err := git.PlainOpenWithOptions(absDir, &git.PlainOpenOptions{
+	if repo, err := git.PlainOpenWithOptions(p.absDir, &git.PlainOpenOptions{
 		DetectDotGit: true,
 	}); err == nil {
 		if storage, ok := repo.Storer.(*filesystem.Storage); ok {
-			gitRoot = path.Dir(storage.Filesystem().Root())
+			p.gitRoot = path.Dir(storage.Filesystem().Root())
 		}
 
 		if remote, err := repo.Remote("origin"); err == nil {
 			if urls := remote.Config().URLs; len(urls) > 0 {
 				if matches := githubRegexp.FindStringSubmatch(urls[0]); len(matches) == 2 {
-					githubUserRepo = matches[1]
+					p.githubUserRepo = matches[1]
 				}
 			}
 		}
 
 		if head, err := repo.Head(); err == nil {
-			gitHash = head.Hash().String()
+			p.gitHash = head.Hash().String()
 		}
 	}
 
-	// Prepare the title/description
+	// Parse code and resolve types
 
-	var title = path.Base(absDir)
-	var description string
+	p.pkgs, err = packages.Load(&packages.Config{
+		Context: ctx,
+		Dir:     p.absDir,
+		Mode:    packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports | packages.NeedDeps | packages.NeedExportsFile | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedTypesSizes | packages.NeedModule,
+	}, "./...")
+	if err != nil {
+		return nil, err
+	}
 
-	if len(gitRoot) > 0 {
-		if t := path.Clean("." + strings.TrimPrefix(absDir, gitRoot)); t != "." {
+	return p, nil
+}
+
+// title is the path of dir relative to the git root, if any.
+func (p *project) title(dir string) string {
+	title := path.Base(dir)
+	if len(p.gitRoot) > 0 {
+		if t := path.Clean("." + strings.TrimPrefix(dir, p.gitRoot)); t != "." {
 			title = t
 		}
-		if len(githubUserRepo) > 0 {
-			url := "https://github.com/" + githubUserRepo
-			if len(gitHash) > 0 {
-				url += "/tree/" + gitHash + "/" + title
-			}
-			description = fmt.Sprintf("Source: [%s](%s)", url, url)
+	}
+	return title
+}
+
+// description links to the sources of dir on GitHub, if possible.
+func (p *project) description(dir string) string {
+	if len(p.gitRoot) == 0 || len(p.githubUserRepo) == 0 {
+		return ""
+	}
+
+	url := "https://github.com/" + p.githubUserRepo
+	if len(p.gitHash) > 0 {
+		url += "/tree/" + p.gitHash + "/" + p.title(dir)
+	}
+	return fmt.Sprintf("Source: [%s](%s)", url, url)
+}
+
+func (p *project) pkgByDir(dir string) *packages.Package {
+	for _, pkg := range p.pkgs {
+		if pkgDir(pkg) == dir {
+			return pkg
 		}
 	}
+	return nil
+}
 
-	// Parse code and resolve types
+// pkgByName finds a package by import path, or by directory when name is a
+// relative or absolute path.
+func (p *project) pkgByName(name string) *packages.Package {
+	if path.IsAbs(name) {
+		return p.pkgByDir(path.Clean(name))
+	}
+	if name == "." || name == ".." || strings.HasPrefix(name, "./") || strings.HasPrefix(name, "../") {
+		return p.pkgByDir(path.Join(p.absDir, name))
+	}
 
-	pkgs, err := packages.Load(&packages.Config{
-		Context: ctx,
-		Dir:     absDir,
-		Mode:    packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports | packages.NeedDeps | packages.NeedExportsFile | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedTypesSizes | packages.NeedModule,
-	}, "./...")
-	if err != nil {
-		return nil, err
+	for _, pkg := range p.pkgs {
+		if pkg.PkgPath == name {
+			return pkg
+		}
 	}
+	return nil
+}
 
+func pkgDir(pkg *packages.Package) string {
+	if len(pkg.GoFiles) == 0 {
+		return ""
+	}
+	return path.Dir(pkg.GoFiles[0])
+}
+
+// document walks the entrypoints to discover endpoints and builds the OpenAPI
+// schema out of them, reporting diagnostics to cfg.
+func (p *project) document(title string, dir string, entrypoints []*packages.Package, cfg Config) *openapi3.T {
 	// Walk the ASTs to discover endpoints
 
-	ev := NewEndpointsVisitor(pkgs)
+	ev := NewEndpointsVisitor(p.pkgs, entrypoints)
+	ev.schemas.Overrides = cfg.Schemas
 	ev.Walk()
+	endpoints := ev.Endpoints()
+	ev.schemas.Finalize()
 
 	// Build the OpenAPI schema
 
@@ -100,8 +252,8 @@ func Reveal(ctx context.Context, dir string) (*openapi3.T, error) {
 		OpenAPI: "3.0.0",
 		Info: &openapi3.Info{
 			Title:       title,
-			Description: description,
-			Version:     gitHash,
+			Description: p.description(dir),
+			Version:     p.gitHash,
 		},
 		Paths: openapi3.Paths{},
 		Components: openapi3.Components{
@@ -109,7 +261,7 @@ func Reveal(ctx context.Context, dir string) (*openapi3.T, error) {
 		},
 	}
 
-	for _, e := range ev.Endpoints() {
+	for _, e := range endpoints {
 		item, ok := doc.Paths[e.Path]
 		if !ok {
 			item = &openapi3.PathItem{}
@@ -145,9 +297,15 @@ func Reveal(ctx context.Context, dir string) (*openapi3.T, error) {
 		}
 	}
 
+	if cfg.Report != nil {
+		for _, d := range ev.Diagnostics() {
+			cfg.Report(d)
+		}
+	}
+
 	//if err := doc.Validate(ctx); err != nil {
 	//return nil, err
 	//}
 
-	return doc, nil
+	return doc
 }
@@ -1,33 +1,162 @@
 package reveal
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"go/types"
+	"strings"
 
 	"github.com/fatih/structtag"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+// Direction tells whether a schema describes what handlers read or write, as
+// a property always written in responses is not necessarily required in
+// requests.
+type Direction int
+
+const (
+	// Request schemas require the properties gin validates as required.
+	Request Direction = iota
+	// Response schemas require the properties always serialized.
+	Response
+)
+
 type SchemaRegistry struct {
 	Schemas openapi3.Schemas
+
+	// Overrides are the schemas of the types analysis gets wrong, indexed by
+	// qualified name (e.g. github.com/shopspring/decimal.Decimal).
+	Overrides map[string]*openapi3.Schema
+
+	// marshaledType infers the type whose JSON a MarshalJSON method writes,
+	// returning nil when it cannot tell.
+	marshaledType func(method *types.Func) types.Type
+
+	// enumValues lists the constants of a named type, as written by method
+	// when it is not nil.
+	enumValues func(named *types.Named, method *types.Func) []enumValue
+
+	// components are named by Finalize, once it is known whether the
+	// requests and responses of a type can share the same one
+	components map[componentKey]*component
+	order      []componentKey
+}
+
+type componentKey struct {
+	name string
+	dir  Direction
+}
+
+type component struct {
+	schema *openapi3.SchemaRef
+	refs   []*openapi3.SchemaRef // to be pointed at the final name
 }
 
 func NewSchemaRegistry() *SchemaRegistry {
 	return &SchemaRegistry{
-		Schemas: openapi3.Schemas{},
+		Schemas:    openapi3.Schemas{},
+		components: map[componentKey]*component{},
+	}
+}
+
+// wellKnownSchemas are the schemas of types whose Go layout has nothing to do
+// with the way they are serialized, indexed by qualified type name.
+var wellKnownSchemas = map[string]func() *openapi3.Schema{
+	"time.Time":                             openapi3.NewDateTimeSchema,
+	"time.Duration":                         openapi3.NewInt64Schema, // in nanoseconds
+	"encoding/json.RawMessage":              openapi3.NewSchema,
+	"encoding/json/jsontext.Value":          openapi3.NewSchema,
+	"encoding/json.Number":                  openapi3.NewFloat64Schema,
+	"net.IP":                                stringFormat("ip"),
+	"math/big.Int":                          openapi3.NewIntegerSchema,
+	"github.com/shopspring/decimal.Decimal": stringFormat("decimal"),
+	"github.com/google/uuid.UUID":           openapi3.NewUUIDSchema,
+	"github.com/google/uuid.NullUUID":       nullableSchema(openapi3.NewUUIDSchema),
+	"github.com/gofrs/uuid.UUID":            openapi3.NewUUIDSchema,
+	"github.com/gofrs/uuid.NullUUID":        nullableSchema(openapi3.NewUUIDSchema),
+	"github.com/satori/go.uuid.UUID":        openapi3.NewUUIDSchema,
+	"mime/multipart.FileHeader":             stringFormat("binary"),
+}
+
+func init() {
+	// github.com/guregu/null and its gopkg.in versions
+	for _, pkg := range []string{"github.com/guregu/null", "github.com/guregu/null/v5", "gopkg.in/guregu/null.v3", "gopkg.in/guregu/null.v4"} {
+		wellKnownSchemas[pkg+".String"] = nullableSchema(openapi3.NewStringSchema)
+		wellKnownSchemas[pkg+".Bool"] = nullableSchema(openapi3.NewBoolSchema)
+		wellKnownSchemas[pkg+".Int"] = nullableSchema(openapi3.NewInt64Schema)
+		wellKnownSchemas[pkg+".Float"] = nullableSchema(openapi3.NewFloat64Schema)
+		wellKnownSchemas[pkg+".Time"] = nullableSchema(openapi3.NewDateTimeSchema)
+	}
+}
+
+func stringFormat(format string) func() *openapi3.Schema {
+	return func() *openapi3.Schema {
+		return openapi3.NewStringSchema().WithFormat(format)
+	}
+}
+
+func nullableSchema(schema func() *openapi3.Schema) func() *openapi3.Schema {
+	return func() *openapi3.Schema {
+		return schema().WithNullable()
 	}
 }
 
+// isWellKnown reports whether ty has a schema in wellKnownSchemas.
+func isWellKnown(ty types.Type) bool {
+	_, ok := wellKnownSchema(flattenPointers(ty))
+	return ok
+}
+
+// wellKnownSchema looks ty up in wellKnownSchemas.
+func wellKnownSchema(ty types.Type) (func() *openapi3.Schema, bool) {
+	named, ok := ty.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil, false
+	}
+	schema, ok := wellKnownSchemas[named.Obj().Pkg().Path()+"."+named.Obj().Name()]
+	return schema, ok
+}
+
+// ToSchemaRef returns the schema of a request of type ty, encoded according
+// to the given struct tag.
 func (sr *SchemaRegistry) ToSchemaRef(ty types.Type, tag string) *openapi3.SchemaRef {
+	return sr.ToSchemaRefFor(ty, tag, Request)
+}
+
+// ToSchemaRefFor returns the schema of ty in the given direction, encoded
+// according to the given struct tag.
+func (sr *SchemaRegistry) ToSchemaRefFor(ty types.Type, tag string, dir Direction) *openapi3.SchemaRef {
 	ty = flattenPointers(ty)
 
+	if schema, ok := sr.override(ty); ok {
+		return &openapi3.SchemaRef{Value: schema}
+	}
+
+	if schema, ok := wellKnownSchema(ty); ok {
+		return &openapi3.SchemaRef{Value: schema()}
+	}
+
 	if named, ok := ty.(*types.Named); ok && named != nil {
 		name := named.Obj().Name()
-		if _, ok := sr.Schemas[name]; !ok {
-			sr.Schemas[name] = &openapi3.SchemaRef{}
-			sr.Schemas[name] = sr.ToSchemaRef(named.Underlying(), tag)
+		if !sameFieldNames(named, tag, "json") {
+			// the same type is serialized differently depending on the tag
+			name += strings.ToUpper(tag)
 		}
-		return &openapi3.SchemaRef{Ref: fmt.Sprintf("#/components/schemas/%s", name)}
+
+		key := componentKey{name, dir}
+		c, ok := sr.components[key]
+		if !ok {
+			c = &component{}
+			sr.components[key] = c
+			sr.order = append(sr.order, key)
+			c.schema = sr.namedSchemaRef(named, tag, dir)
+		}
+
+		ref := &openapi3.SchemaRef{Ref: componentRef(name)}
+		c.refs = append(c.refs, ref)
+		return ref
 	}
 
 	switch t := ty.(type) {
@@ -47,7 +176,8 @@ func (sr *SchemaRegistry) ToSchemaRef(ty types.Type, tag string) *openapi3.Schem
 		case "string":
 			return &openapi3.SchemaRef{Value: openapi3.NewStringSchema()}
 		default:
-			return nil
+			// complex numbers cannot be serialized
+			return &openapi3.SchemaRef{Value: openapi3.NewSchema()}
 		}
 
 	case *types.Interface:
@@ -62,19 +192,43 @@ func (sr *SchemaRegistry) ToSchemaRef(ty types.Type, tag string) *openapi3.Schem
 		return &openapi3.SchemaRef{
 			Value: &openapi3.Schema{
 				Type:                 openapi3.TypeObject,
-				AdditionalProperties: sr.ToSchemaRef(t.Elem(), tag),
+				AdditionalProperties: sr.ToSchemaRefFor(t.Elem(), tag, dir),
 			},
 		}
 
 	case *types.Slice:
+		// byte slices are base64 encoded
+		if elem, ok := t.Elem().Underlying().(*types.Basic); ok && elem.Kind() == types.Byte {
+			return &openapi3.SchemaRef{Value: openapi3.NewBytesSchema()}
+		}
+
 		return &openapi3.SchemaRef{
 			Value: &openapi3.Schema{
 				Type:  openapi3.TypeArray,
-				Items: sr.ToSchemaRef(t.Elem(), tag),
+				Items: sr.ToSchemaRefFor(t.Elem(), tag, dir),
+			},
+		}
+
+	case *types.Array:
+		size := uint64(t.Len())
+		return &openapi3.SchemaRef{
+			Value: &openapi3.Schema{
+				Type:     openapi3.TypeArray,
+				Items:    sr.ToSchemaRefFor(t.Elem(), tag, dir),
+				MinItems: size,
+				MaxItems: &size,
 			},
 		}
 
+	case *types.Chan, *types.Signature:
+		// channels and functions cannot be serialized
+		return &openapi3.SchemaRef{Value: openapi3.NewSchema()}
+
 	case *types.Struct:
+		if schema, ok := sr.marshalerSchemaRef(ty, tag, dir); ok {
+			return schema
+		}
+
 		out := &openapi3.SchemaRef{
 			Value: &openapi3.Schema{
 				Type:       openapi3.TypeObject,
@@ -82,42 +236,266 @@ func (sr *SchemaRegistry) ToSchemaRef(ty types.Type, tag string) *openapi3.Schem
 			},
 		}
 
-		for i := 0; i < t.NumFields(); i++ {
-			field := t.Field(i)
+		for _, f := range structFields(t, tag) {
+			schema := sr.ToSchemaRefFor(f.field.Type(), tag, dir)
 
-			// ignore private fields
-			if !field.Exported() {
-				continue
+			required := dir == Response && f.alwaysSerialized()
+			if binding, err := f.tags.Get("binding"); err == nil {
+				var validated bool
+				schema, validated = applyBindingTag(schema, f.field.Type(), binding.Value())
+				required = required || dir == Request && validated
 			}
 
-			tags, err := structtag.Parse(t.Tag(i))
-			if err != nil {
-				continue
+			if _, ok := f.field.Type().(*types.Pointer); ok {
+				schema = nullable(schema)
+			}
+
+			if required {
+				out.Value.Required = append(out.Value.Required, f.name)
+			}
+			out.Value.Properties[f.name] = schema
+		}
+
+		return out
+	}
+
+	// type parameters are documented as their constraint
+	if underlying := ty.Underlying(); underlying != ty {
+		return sr.ToSchemaRefFor(underlying, tag, dir)
+	}
+
+	panic(fmt.Errorf("unsupported type %#v", ty))
+}
+
+// override returns a copy of the schema ty is overridden with, if any.
+func (sr *SchemaRegistry) override(ty types.Type) (*openapi3.Schema, bool) {
+	named, ok := ty.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil, false
+	}
+	schema, ok := sr.Overrides[named.Obj().Pkg().Path()+"."+named.Obj().Name()]
+	if !ok || schema == nil {
+		return nil, false
+	}
+	copied := *schema
+	return &copied, true
+}
+
+// namedSchemaRef returns the schema of a named type, which is the one of its
+// underlying type unless it marshals itself.
+func (sr *SchemaRegistry) namedSchemaRef(named *types.Named, tag string, dir Direction) *openapi3.SchemaRef {
+	if schema, ok := sr.marshalerSchemaRef(named, tag, dir); ok {
+		return schema
+	}
+
+	schema := sr.ToSchemaRefFor(named.Underlying(), tag, dir)
+	if _, ok := named.Underlying().(*types.Basic); ok && sr.enumValues != nil {
+		withEnum(schema.Value, sr.enumValues(named, nil))
+	}
+	return schema
+}
+
+// marshalerSchemaRef returns the schema of what ty is written as when it
+// marshals itself, possibly through a method promoted from an embedded field.
+func (sr *SchemaRegistry) marshalerSchemaRef(ty types.Type, tag string, dir Direction) (*openapi3.SchemaRef, bool) {
+	if tag == "json" {
+		if method, index := marshaler(ty, "MarshalJSON"); method != nil {
+			if len(index) > 1 {
+				// promoted from an embedded field, which is what is written
+				return sr.ToSchemaRefFor(embeddedType(ty, index), tag, dir), true
 			}
 
-			property := field.Name()
-			for _, key := range tags.Keys() {
-				if key == tag {
-					if value, err := tags.Get(tag); err == nil {
-						property = value.Name
-						break
-					}
+			if sr.marshaledType != nil {
+				if marshaled := sr.marshaledType(method); marshaled != nil {
+					return sr.ToSchemaRefFor(marshaled, tag, dir), true
 				}
 			}
+			return &openapi3.SchemaRef{Value: openapi3.NewSchema()}, true
+		}
+	}
+
+	if method, index := marshaler(ty, "MarshalText"); method != nil {
+		schema := openapi3.NewStringSchema()
+		if named, ok := ty.(*types.Named); ok && len(index) == 1 && sr.enumValues != nil {
+			withEnum(schema, sr.enumValues(named, method))
+		}
+		return &openapi3.SchemaRef{Value: schema}, true
+	}
+
+	return nil, false
+}
+
+// withEnum restricts schema to the given values, naming them after their
+// constants and listing their docs in the description.
+func withEnum(schema *openapi3.Schema, values []enumValue) {
+	if len(values) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(values))
+	docs := make([]string, 0, len(values))
+	var description []string
+	for _, value := range values {
+		schema.Enum = append(schema.Enum, value.value)
+		names = append(names, value.name)
+		docs = append(docs, value.doc)
+		if value.doc != "" {
+			description = append(description, fmt.Sprintf("- `%v`: %s", value.value, strings.ReplaceAll(value.doc, "\n", " ")))
+		}
+	}
+
+	if schema.Extensions == nil {
+		schema.Extensions = map[string]interface{}{}
+	}
+	schema.Extensions["x-enum-varnames"] = names
+	if len(description) > 0 {
+		schema.Extensions["x-enum-descriptions"] = docs
+		schema.Description = strings.Join(description, "\n")
+	}
+}
+
+// marshaler returns the method of *ty marshaling it (MarshalJSON or
+// MarshalText) along with its index path, if it implements it.
+func marshaler(ty types.Type, name string) (*types.Func, []int) {
+	sel := types.NewMethodSet(types.NewPointer(ty)).Lookup(nil, name)
+	if sel == nil {
+		return nil, nil
+	}
+
+	method, ok := sel.Obj().(*types.Func)
+	if !ok {
+		return nil, nil
+	}
+
+	sig, ok := method.Type().(*types.Signature)
+	if !ok || sig.Params().Len() != 0 || sig.Results().Len() != 2 {
+		return nil, nil
+	}
+	if !types.Identical(sig.Results().At(0).Type(), types.NewSlice(types.Typ[types.Byte])) || sig.Results().At(1).Type().String() != "error" {
+		return nil, nil
+	}
+
+	return method, sel.Index()
+}
+
+// embeddedType returns the type of the embedded field a method at the given
+// index path is promoted from.
+func embeddedType(ty types.Type, index []int) types.Type {
+	for _, i := range index[:len(index)-1] {
+		strct, ok := flattenPointers(ty).Underlying().(*types.Struct)
+		if !ok {
+			break
+		}
+		ty = strct.Field(i).Type()
+	}
+	return ty
+}
 
-			if property != "-" {
-				out.Value.Properties[property] = sr.ToSchemaRef(field.Type(), tag)
+// Finalize names the components and fills Schemas with them. The requests and
+// responses of a type share the same component unless their schemas differ, in
+// which case the request one is suffixed with Input.
+func (sr *SchemaRegistry) Finalize() {
+	split := map[string]bool{}
+
+	// splitting a type may split the types referring to it
+	for changed := true; changed; {
+		changed = false
+		sr.pointRefs(split)
+
+		for _, key := range sr.order {
+			if key.dir != Request || split[key.name] {
+				continue
+			}
+			res, ok := sr.components[componentKey{key.name, Response}]
+			if ok && !sameSchema(sr.components[key].schema, res.schema) {
+				split[key.name] = true
+				changed = true
 			}
 		}
+	}
 
-		return out
+	for _, key := range sr.order {
+		sr.Schemas[componentName(key, split)] = sr.components[key].schema
 	}
+}
 
-	panic(fmt.Errorf("unsupported type %#v", ty))
+func (sr *SchemaRegistry) pointRefs(split map[string]bool) {
+	for key, c := range sr.components {
+		for _, ref := range c.refs {
+			ref.Ref = componentRef(componentName(key, split))
+		}
+	}
+}
+
+func componentName(key componentKey, split map[string]bool) string {
+	if key.dir == Request && split[key.name] {
+		return key.name + "Input"
+	}
+	return key.name
+}
+
+func componentRef(name string) string {
+	return fmt.Sprintf("#/components/schemas/%s", name)
+}
+
+func sameSchema(a, b *openapi3.SchemaRef) bool {
+	adata, aerr := json.Marshal(a)
+	bdata, berr := json.Marshal(b)
+	return aerr == nil && berr == nil && bytes.Equal(adata, bdata)
+}
+
+// nullable marks schema as accepting null.
+func nullable(schema *openapi3.SchemaRef) *openapi3.SchemaRef {
+	switch {
+	case schema == nil:
+		return nil
+	case schema.Ref != "":
+		// nothing can be added next to a reference
+		return &openapi3.SchemaRef{Value: &openapi3.Schema{AllOf: openapi3.SchemaRefs{schema}, Nullable: true}}
+	case schema.Value != nil:
+		schema.Value.Nullable = true
+	}
+	return schema
+}
+
+// sameFieldNames reports whether the fields of ty are named the same way by
+// both struct tags.
+func sameFieldNames(ty types.Type, tag1, tag2 string) bool {
+	if tag1 == tag2 {
+		return true
+	}
+
+	strct, ok := ty.Underlying().(*types.Struct)
+	if !ok {
+		return true
+	}
+
+	for i := 0; i < strct.NumFields(); i++ {
+		tags, err := structtag.Parse(strct.Tag(i))
+		if err != nil {
+			continue
+		}
+
+		name1, name2 := strct.Field(i).Name(), strct.Field(i).Name()
+		if value, err := tags.Get(tag1); err == nil {
+			name1 = value.Name
+		}
+		if value, err := tags.Get(tag2); err == nil {
+			name2 = value.Name
+		}
+		if name1 != name2 {
+			return false
+		}
+	}
+
+	return true
 }
 
+// flattenPointers returns the type ty points to, if any, aliases being
+// replaced by the type they stand for.
 func flattenPointers(ty types.Type) types.Type {
 	for {
+		ty = types.Unalias(ty)
 		if ptr, ok := ty.(*types.Pointer); ok && ptr != nil {
 			ty = ptr.Elem()
 		} else {
@@ -0,0 +1,254 @@
+package reveal
+
+import (
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// applyBindingTag translates the go-playground/validator rules gin reads from
+// `binding:"..."` tags into schema constraints, reporting whether the field is
+// required. Rules that cannot be expressed are ignored.
+func applyBindingTag(schema *openapi3.SchemaRef, ty types.Type, tag string) (*openapi3.SchemaRef, bool) {
+	var required bool
+	var constraints []func(*openapi3.Schema)
+
+	kind := constraintKind(ty)
+
+	rules := strings.Split(tag, ",")
+	for i := 0; i < len(rules); i++ {
+		rule := rules[i]
+		name, param := rule, ""
+		if i := strings.IndexByte(rule, '='); i >= 0 {
+			name, param = rule[:i], rule[i+1:]
+		}
+
+		// alternatives (email|url) cannot be told apart
+		if strings.Contains(rule, "|") {
+			continue
+		}
+
+		switch name {
+		case "required":
+			required = true
+
+		case "keys":
+			// rules on map keys have no OpenAPI counterpart
+			for i < len(rules) && rules[i] != "endkeys" {
+				i++
+			}
+
+		case "dive":
+			// the remaining rules apply to the elements of the collection
+			constraints = append(constraints, dive(ty, strings.Join(rules[i+1:], ",")))
+			i = len(rules)
+
+		case "min", "gte":
+			if n, err := strconv.ParseFloat(param, 64); err == nil {
+				constraints = append(constraints, lowerBound(kind, n, false))
+			}
+
+		case "max", "lte":
+			if n, err := strconv.ParseFloat(param, 64); err == nil {
+				constraints = append(constraints, upperBound(kind, n, false))
+			}
+
+		case "gt":
+			if n, err := strconv.ParseFloat(param, 64); err == nil {
+				constraints = append(constraints, lowerBound(kind, n, true))
+			}
+
+		case "lt":
+			if n, err := strconv.ParseFloat(param, 64); err == nil {
+				constraints = append(constraints, upperBound(kind, n, true))
+			}
+
+		case "len", "eq":
+			if n, err := strconv.ParseFloat(param, 64); err == nil && (name == "len" || kind == numberKind) {
+				constraints = append(constraints, lowerBound(kind, n, false), upperBound(kind, n, false))
+			}
+
+		case "oneof":
+			var enum []interface{}
+			for _, value := range oneofRegexp.FindAllString(param, -1) {
+				if kind == numberKind {
+					if n, err := strconv.ParseFloat(value, 64); err == nil {
+						enum = append(enum, n)
+						continue
+					}
+				}
+				enum = append(enum, strings.Trim(value, "'"))
+			}
+			constraints = append(constraints, func(s *openapi3.Schema) { s.Enum = enum })
+
+		case "uuid", "uuid3", "uuid4", "uuid5", "uuid_rfc4122", "uuid3_rfc4122", "uuid4_rfc4122", "uuid5_rfc4122":
+			constraints = append(constraints, format("uuid"))
+
+		case "email":
+			constraints = append(constraints, format("email"))
+
+		case "url", "uri", "http_url":
+			constraints = append(constraints, format("uri"))
+
+		case "hostname", "hostname_rfc1123":
+			constraints = append(constraints, format("hostname"))
+
+		case "ipv4", "ipv6":
+			constraints = append(constraints, format(name))
+
+		case "datetime":
+			switch param {
+			case time.RFC3339, time.RFC3339Nano:
+				constraints = append(constraints, format("date-time"))
+			case "2006-01-02":
+				constraints = append(constraints, format("date"))
+			}
+
+		default:
+			if pattern, ok := patterns[name]; ok {
+				constraints = append(constraints, func(s *openapi3.Schema) { s.Pattern = pattern })
+			}
+		}
+	}
+
+	if len(constraints) == 0 {
+		return schema, required
+	}
+
+	if schema == nil {
+		schema = &openapi3.SchemaRef{Value: &openapi3.Schema{}}
+	} else if schema.Value == nil {
+		// constraints cannot be added next to a reference
+		schema = &openapi3.SchemaRef{Value: &openapi3.Schema{AllOf: openapi3.SchemaRefs{schema}}}
+	}
+
+	for _, constraint := range constraints {
+		constraint(schema.Value)
+	}
+
+	return schema, required
+}
+
+// oneofRegexp splits the values of oneof rules, which may be quoted to hold
+// spaces, as validator does.
+var oneofRegexp = regexp.MustCompile(`'[^']*'|\S+`)
+
+// patterns are the regular expressions of the validator rules on characters.
+var patterns = map[string]string{
+	"alpha":       "^[a-zA-Z]+$",
+	"alphanum":    "^[a-zA-Z0-9]+$",
+	"numeric":     "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"number":      "^[0-9]+$",
+	"hexadecimal": "^(0[xX])?[0-9a-fA-F]+$",
+	"lowercase":   "^[^A-Z]*$",
+	"uppercase":   "^[^a-z]*$",
+}
+
+// dive applies rules to the items of arrays and the values of maps.
+func dive(ty types.Type, rules string) func(*openapi3.Schema) {
+	return func(s *openapi3.Schema) {
+		if ty == nil || rules == "" {
+			return
+		}
+
+		switch t := flattenPointers(ty).Underlying().(type) {
+		case *types.Slice:
+			if s.Items != nil {
+				s.Items, _ = applyBindingTag(s.Items, t.Elem(), rules)
+			}
+		case *types.Array:
+			if s.Items != nil {
+				s.Items, _ = applyBindingTag(s.Items, t.Elem(), rules)
+			}
+		case *types.Map:
+			if s.AdditionalProperties != nil {
+				s.AdditionalProperties, _ = applyBindingTag(s.AdditionalProperties, t.Elem(), rules)
+			}
+		}
+	}
+}
+
+type kindOfConstraint int
+
+const (
+	numberKind kindOfConstraint = iota
+	stringKind
+	arrayKind
+	objectKind
+)
+
+// constraintKind tells how validator's size rules (min, max, len...) apply to
+// ty: on values for numbers, on lengths for strings and on sizes for
+// collections.
+func constraintKind(ty types.Type) kindOfConstraint {
+	if ty == nil {
+		return stringKind
+	}
+
+	switch t := flattenPointers(ty).Underlying().(type) {
+	case *types.Basic:
+		if t.Info()&types.IsNumeric != 0 {
+			return numberKind
+		}
+	case *types.Slice, *types.Array:
+		return arrayKind
+	case *types.Map:
+		return objectKind
+	}
+
+	return stringKind
+}
+
+func lowerBound(kind kindOfConstraint, n float64, exclusive bool) func(*openapi3.Schema) {
+	return func(s *openapi3.Schema) {
+		switch kind {
+		case numberKind:
+			s.Min = &n
+			s.ExclusiveMin = exclusive
+		case stringKind:
+			s.MinLength = sizeBound(n, exclusive, 1)
+		case arrayKind:
+			s.MinItems = sizeBound(n, exclusive, 1)
+		case objectKind:
+			s.MinProps = sizeBound(n, exclusive, 1)
+		}
+	}
+}
+
+func upperBound(kind kindOfConstraint, n float64, exclusive bool) func(*openapi3.Schema) {
+	return func(s *openapi3.Schema) {
+		size := sizeBound(n, exclusive, -1)
+		switch kind {
+		case numberKind:
+			s.Max = &n
+			s.ExclusiveMax = exclusive
+		case stringKind:
+			s.MaxLength = &size
+		case arrayKind:
+			s.MaxItems = &size
+		case objectKind:
+			s.MaxProps = &size
+		}
+	}
+}
+
+// sizeBound converts a bound to a size, shifting exclusive bounds by delta.
+func sizeBound(n float64, exclusive bool, delta int) uint64 {
+	if exclusive {
+		n += float64(delta)
+	}
+	if n < 0 {
+		return 0
+	}
+	return uint64(n)
+}
+
+func format(f string) func(*openapi3.Schema) {
+	return func(s *openapi3.Schema) {
+		s.Format = f
+	}
+}
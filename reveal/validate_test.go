@@ -0,0 +1,166 @@
+package reveal
+
+import (
+	"encoding/json"
+	"go/types"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestApplyBindingTag(t *testing.T) {
+	var (
+		integer = types.Typ[types.Int]
+		str     = types.Typ[types.String]
+		strs    = types.NewSlice(str)
+		dict    = types.NewMap(str, str)
+	)
+
+	tests := []struct {
+		name     string
+		schema   *openapi3.SchemaRef
+		ty       types.Type
+		tag      string
+		want     string // JSON of the schema
+		required bool
+	}{
+		{
+			name:     "required",
+			schema:   openapi3.NewStringSchema().NewRef(),
+			ty:       str,
+			tag:      "required",
+			want:     `{"type":"string"}`,
+			required: true,
+		},
+		{
+			name:   "numbers are bounded by value",
+			schema: openapi3.NewIntegerSchema().NewRef(),
+			ty:     integer,
+			tag:    "min=1,max=10",
+			want:   `{"maximum":10,"minimum":1,"type":"integer"}`,
+		},
+		{
+			name:   "exclusive bounds on numbers",
+			schema: openapi3.NewIntegerSchema().NewRef(),
+			ty:     integer,
+			tag:    "gt=0,lt=5",
+			want:   `{"exclusiveMaximum":true,"exclusiveMinimum":true,"maximum":5,"minimum":0,"type":"integer"}`,
+		},
+		{
+			name:   "strings are bounded by length",
+			schema: openapi3.NewStringSchema().NewRef(),
+			ty:     str,
+			tag:    "gte=2,lte=8",
+			want:   `{"maxLength":8,"minLength":2,"type":"string"}`,
+		},
+		{
+			name:   "exclusive bounds on lengths are shifted",
+			schema: openapi3.NewStringSchema().NewRef(),
+			ty:     str,
+			tag:    "gt=2,lt=8",
+			want:   `{"maxLength":7,"minLength":3,"type":"string"}`,
+		},
+		{
+			name:   "collections are bounded by size",
+			schema: openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema()).NewRef(),
+			ty:     strs,
+			tag:    "len=2",
+			want:   `{"items":{"type":"string"},"maxItems":2,"minItems":2,"type":"array"}`,
+		},
+		{
+			name:   "eq only bounds numbers",
+			schema: openapi3.NewStringSchema().NewRef(),
+			ty:     str,
+			tag:    "eq=5",
+			want:   `{"type":"string"}`,
+		},
+		{
+			name:   "oneof on strings",
+			schema: openapi3.NewStringSchema().NewRef(),
+			ty:     str,
+			tag:    "oneof=red 'dark blue'",
+			want:   `{"enum":["red","dark blue"],"type":"string"}`,
+		},
+		{
+			name:   "oneof on numbers",
+			schema: openapi3.NewIntegerSchema().NewRef(),
+			ty:     integer,
+			tag:    "oneof=1 2",
+			want:   `{"enum":[1,2],"type":"integer"}`,
+		},
+		{
+			name:   "formats",
+			schema: openapi3.NewStringSchema().NewRef(),
+			ty:     str,
+			tag:    "uuid4",
+			want:   `{"format":"uuid","type":"string"}`,
+		},
+		{
+			name:   "dates",
+			schema: openapi3.NewStringSchema().NewRef(),
+			ty:     str,
+			tag:    "datetime=2006-01-02",
+			want:   `{"format":"date","type":"string"}`,
+		},
+		{
+			name:   "patterns",
+			schema: openapi3.NewStringSchema().NewRef(),
+			ty:     str,
+			tag:    "alphanum",
+			want:   `{"pattern":"^[a-zA-Z0-9]+$","type":"string"}`,
+		},
+		{
+			name:   "alternatives are ignored",
+			schema: openapi3.NewStringSchema().NewRef(),
+			ty:     str,
+			tag:    "email|url",
+			want:   `{"type":"string"}`,
+		},
+		{
+			name:   "dive applies to items",
+			schema: openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema()).NewRef(),
+			ty:     strs,
+			tag:    "max=3,dive,min=1",
+			want:   `{"items":{"minLength":1,"type":"string"},"maxItems":3,"type":"array"}`,
+		},
+		{
+			name:   "dive applies to map values, not keys",
+			schema: openapi3.NewObjectSchema().WithAdditionalProperties(openapi3.NewStringSchema()).NewRef(),
+			ty:     dict,
+			tag:    "dive,keys,alpha,endkeys,max=5",
+			want:   `{"additionalProperties":{"maxLength":5,"type":"string"},"type":"object"}`,
+		},
+		{
+			name:     "rules on keys are skipped",
+			schema:   openapi3.NewObjectSchema().NewRef(),
+			ty:       dict,
+			tag:      "keys,alpha,endkeys,required",
+			want:     `{"type":"object"}`,
+			required: true,
+		},
+		{
+			name:   "references are wrapped",
+			schema: openapi3.NewSchemaRef("#/components/schemas/Name", nil),
+			ty:     str,
+			tag:    "min=1",
+			want:   `{"allOf":[{"$ref":"#/components/schemas/Name"}],"minLength":1}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema, required := applyBindingTag(test.schema, test.ty, test.tag)
+
+			got, err := json.Marshal(schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+			if required != test.required {
+				t.Errorf("got required %v, want %v", required, test.required)
+			}
+		})
+	}
+}
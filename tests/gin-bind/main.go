@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type Book struct {
+	Title  string `json:"title" xml:"Title" yaml:"title" form:"title"`
+	Author string `json:"author" xml:"Author" yaml:"author" form:"author"`
+}
+
+type Note struct {
+	Text string `xml:"text" json:"content"`
+}
+
+// Event stands for a generated protobuf message.
+type Event struct {
+	Name string `json:"name,omitempty" form:"name"`
+}
+
+func (*Event) Reset()         {}
+func (*Event) String() string { return "" }
+func (*Event) ProtoMessage()  {}
+
+type Filter struct {
+	Author string `form:"author"`
+	Page   string `form:"page"`
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should document every content type ShouldBind accepts
+	router.POST("/books", func(c *gin.Context) {
+		var book Book
+		if err := c.ShouldBind(&book); err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		c.JSON(http.StatusCreated, book)
+	})
+
+	// it should document query parameters bound by ShouldBind in GET handlers
+	router.GET("/books", func(c *gin.Context) {
+		var filter Filter
+		_ = c.Bind(&filter)
+	})
+
+	// it should use the struct tag read by the explicit binding
+	router.POST("/notes", func(c *gin.Context) {
+		var note Note
+		_ = c.ShouldBindWith(&note, binding.XML)
+	})
+
+	// it should document bodies bound with ShouldBindBodyWith
+	router.PUT("/notes", func(c *gin.Context) {
+		var note Note
+		if err := c.ShouldBindBodyWith(&note, binding.JSON); err != nil {
+			_ = c.ShouldBindBodyWith(&note, binding.YAML)
+		}
+	})
+
+	// it should document protobuf and msgpack bodies
+	router.PATCH("/notes", func(c *gin.Context) {
+		var note Note
+		if c.ContentType() == binding.MIMEMSGPACK {
+			_ = c.MustBindWith(&note, binding.MsgPack)
+		} else {
+			_ = c.ShouldBindXML(&note)
+		}
+	})
+
+	// it should document protobuf bodies for ShouldBind on protobuf messages
+	router.POST("/events", func(c *gin.Context) {
+		var event Event
+		_ = c.ShouldBind(&event)
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	router := gin.Default()
+
+	// it should type path parameters parsed as integers
+	router.GET("/users/:id", func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.Abort()
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": id})
+	})
+
+	// it should type query and header parameters parsed through variables
+	router.GET("/events", func(c *gin.Context) {
+		active := c.Query("active")
+		if _, err := strconv.ParseBool(active); err != nil {
+			_ = c.AbortWithError(http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		since, _ := time.Parse(time.RFC3339, c.Query("since"))
+		limit, _ := strconv.ParseUint(c.DefaultQuery("limit", "10"), 10, 64)
+		ratio, _ := strconv.ParseFloat(c.GetHeader("X-Ratio"), 64)
+		day, _ := time.Parse("2006-01-02", c.Query("day"))
+
+		c.JSON(http.StatusOK, gin.H{"since": since, "limit": limit, "ratio": ratio, "day": day})
+	})
+
+	// it should type form fields parsed as integers
+	router.POST("/events", func(c *gin.Context) {
+		if _, err := strconv.ParseInt(c.PostForm("count"), 10, 64); err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+		}
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Page struct {
+	Page int `form:"page" binding:"required,min=1"`
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should merge parameters read several times
+	router.GET("/items", func(c *gin.Context) {
+		_ = c.Query("page")
+		_ = c.DefaultQuery("page", "1")
+
+		var page Page
+		_ = c.ShouldBindQuery(&page)
+
+		_ = c.GetHeader("X-Request-ID")
+		_ = c.Request.Header.Get("X-Request-ID")
+		_ = c.GetHeader("x-request-id") // header names are case insensitive
+	})
+
+	// it should merge path parameters read again by the handlers
+	items := router.Group("/items/:id")
+	{
+		items.GET("/stock", func(c *gin.Context) {
+			_, _ = strconv.Atoi(c.Param("id"))
+		})
+
+		// it should report conflicting declarations
+		items.DELETE("/stock", func(c *gin.Context) {
+			_, _ = strconv.ParseBool(c.Query("force"))
+			_, _ = strconv.Atoi(c.Query("force"))
+		})
+	}
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
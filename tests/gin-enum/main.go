@@ -0,0 +1,99 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrderStatus string
+
+const (
+	// StatusPending waits for the payment.
+	StatusPending OrderStatus = "pending"
+	StatusPaid    OrderStatus = "paid" // paid but not shipped yet
+	StatusShipped OrderStatus = "shipped"
+)
+
+// StatusDefault is the status of new orders.
+const StatusDefault = StatusPending
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+
+	priorityCount
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	}
+	return "unknown"
+}
+
+type Channel int
+
+const (
+	ChannelWeb Channel = iota + 1
+	ChannelMobile
+	// ChannelStore is for purchases in person.
+	ChannelStore
+)
+
+func (c Channel) String() string {
+	switch c {
+	case ChannelWeb:
+		return "web"
+	case ChannelMobile:
+		return "mobile"
+	case ChannelStore:
+		return "store"
+	default:
+		return ""
+	}
+}
+
+func (c Channel) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+type Order struct {
+	Status   OrderStatus `json:"status"`
+	Priority Priority    `json:"priority"`
+	Channel  Channel     `json:"channel"`
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should list the constants of the types of the fields as enums,
+	// written through MarshalText when there is one
+	router.GET("/orders/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, Order{})
+	})
+
+	// it should document enums in query parameters
+	router.GET("/orders", func(c *gin.Context) {
+		var filter struct {
+			Status OrderStatus `form:"status"`
+		}
+		if err := c.ShouldBindQuery(&filter); err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		c.JSON(http.StatusOK, []Order{})
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
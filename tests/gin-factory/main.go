@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Item struct {
+	ID string
+}
+
+type Service struct{}
+
+type Handler struct{}
+
+func (h *Handler) listItems(svc *Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_ = c.Query("page")
+		c.JSON(http.StatusOK, []Item{})
+	}
+}
+
+func requireHeader(name string, status int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(name) == "" {
+			c.AbortWithStatus(status)
+		}
+	}
+}
+
+func either(fallback bool) func(*gin.Context) {
+	if fallback {
+		return func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		}
+	}
+
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, Item{})
+	}
+	return handler
+}
+
+func newGroup(r *gin.Engine, prefix string) *gin.RouterGroup {
+	return r.Group(prefix)
+}
+
+func main() {
+	router := gin.Default()
+	h := &Handler{}
+	svc := &Service{}
+
+	// it should support handler factories
+	router.GET("/items", h.listItems(svc))
+
+	// it should fold constants passed to handler factories
+	router.GET("/secured", requireHeader("X-Token", http.StatusUnauthorized))
+
+	// it should support factories returning several handlers
+	router.GET("/either", either(true))
+
+	// it should support conversions to gin.HandlerFunc
+	router.GET("/converted", gin.HandlerFunc(func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+	}))
+
+	// it should support router groups built by factories
+	admin := newGroup(router, "/admin")
+	admin.GET("/stats", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func listUsers(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	router := gin.Default()
+
+	// it should follow functions declared in other files of the package
+	v1 := router.Group("/v1")
+	registerRoutes(v1)
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
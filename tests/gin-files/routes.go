@@ -0,0 +1,14 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func registerRoutes(r *gin.RouterGroup) {
+	r.GET("/users", listUsers)
+}
+
+// it should leave out the routes of functions main never calls
+func unused(r *gin.Engine) {
+	r.GET("/dead", listUsers)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type LoginForm struct {
+	// Name of the user
+	User     string `form:"user" binding:"required"`
+	Password string `form:"password" binding:"required,min=8"`
+	Remember bool   `form:"remember"`
+}
+
+type AvatarForm struct {
+	Name   string                `form:"name"`
+	Avatar *multipart.FileHeader `form:"avatar" binding:"required"`
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should document url-encoded and multipart bodies from PostForm
+	router.POST("/messages", func(c *gin.Context) {
+		message := c.PostForm("message")
+		nick := c.DefaultPostForm("nick", "anonymous")
+		tags := c.PostFormArray("tags")
+		meta := c.PostFormMap("meta")
+
+		c.JSON(http.StatusOK, gin.H{"message": message, "nick": nick, "tags": tags, "meta": meta})
+	})
+
+	// it should document multipart bodies from FormFile
+	router.POST("/upload", func(c *gin.Context) {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		_ = c.SaveUploadedFile(file, "/tmp/"+file.Filename)
+		c.String(http.StatusOK, "uploaded")
+	})
+
+	// it should document the fields of structs bound with binding.Form
+	router.POST("/login", func(c *gin.Context) {
+		var form LoginForm
+		if err := c.ShouldBindWith(&form, binding.Form); err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	})
+
+	// it should document files of structs bound with binding.FormMultipart
+	router.PUT("/avatar", func(c *gin.Context) {
+		var form AvatarForm
+		_ = c.MustBindWith(&form, binding.FormMultipart)
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
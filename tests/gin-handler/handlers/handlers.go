@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ListOrders(c *gin.Context) {
+	_ = c.GetHeader("X-Shop")
+	c.JSON(http.StatusOK, &[]struct{ ID string }{})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rekki/reveal/tests/gin-handler/handlers"
+)
+
+type Users struct{}
+
+func (u *Users) Create(c *gin.Context) {
+	c.JSON(http.StatusCreated, &struct{ ID string }{})
+}
+
+func listUsers(c *gin.Context) {
+	_ = c.Query("page")
+	c.JSON(http.StatusOK, &[]struct{ ID string }{})
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should support named functions
+	router.GET("/users", listUsers)
+
+	// it should support method values
+	u := &Users{}
+	router.POST("/users", u.Create)
+
+	// it should support package-qualified functions
+	router.GET("/orders", handlers.ListOrders)
+
+	// it should support handlers stored in variables
+	handler := func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	}
+	router.DELETE("/users", handler)
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
@@ -10,7 +10,8 @@ func main() {
 	// it should support inbound header parameters
 	router.GET("/header-inbound-1", func(c *gin.Context) {
 		_ = c.GetHeader("Authorization")
-		//_ = c.Request.Header.Get("ETag") // TODO
+		_ = c.Request.Header.Get("ETag")
+		_ = c.Request.Header.Values("Accept-Language")
 	})
 
 	// it should support inbound header parameters via struct binding
@@ -41,6 +42,29 @@ func main() {
 		_ = c.BindHeader(&headerB)
 	})
 
+	// it should support inbound header parameters via the request headers
+	router.GET("/header-inbound-4", func(c *gin.Context) {
+		headers := c.Request.Header
+		_ = headers.Get("If-None-Match")
+
+		// response headers are not parameters
+		_ = c.Writer.Header().Get("Content-Type")
+	})
+
+	// it should support cookie parameters
+	router.GET("/cookie", func(c *gin.Context) {
+		_, _ = c.Cookie("session")
+		_, _ = c.Request.Cookie("theme")
+	})
+
+	// it should support query parameters via the request
+	router.GET("/request-query", func(c *gin.Context) {
+		_ = c.Request.URL.Query().Get("page")
+		query := c.Request.URL.Query()
+		_ = query.Get("size")
+		_ = c.Request.FormValue("sort")
+	})
+
 	if err := router.Run(":8080"); err != nil {
 		panic(err)
 	}
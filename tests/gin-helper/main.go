@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateUser struct {
+	Name string `json:"name"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+type Page struct {
+	Items []string `json:"items"`
+}
+
+func bindAndValidate(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+func respondError(c *gin.Context, status int, err error) {
+	c.AbortWithStatusJSON(status, Error{Message: err.Error()})
+}
+
+func writePage(c *gin.Context, items []string) {
+	_ = c.DefaultQuery("cursor", "0")
+	writeJSON(c, http.StatusOK, Page{Items: items})
+}
+
+func writeJSON(c *gin.Context, status int, data interface{}) {
+	c.JSON(status, data)
+}
+
+// it should stop following recursive helpers
+func retry(c *gin.Context, n int) {
+	if n > 0 {
+		retry(c, n-1)
+	}
+	c.Status(http.StatusServiceUnavailable)
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should follow helpers taking the gin context
+	router.POST("/users", func(c *gin.Context) {
+		var req CreateUser
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	// it should follow helpers transitively
+	router.GET("/users", func(c *gin.Context) {
+		writePage(c, nil)
+	})
+
+	router.GET("/retry", func(c *gin.Context) {
+		retry(c, 3)
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
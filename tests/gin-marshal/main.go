@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Level is written as its name.
+type Level int
+
+func (l Level) MarshalText() ([]byte, error) {
+	switch l {
+	case 0:
+		return []byte("low"), nil
+	default:
+		return []byte("high"), nil
+	}
+}
+
+// Money is written as an object with a formatted amount.
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   float64 `json:"amount"`
+		Currency string  `json:"currency"`
+	}{float64(m.Cents) / 100, m.Currency})
+}
+
+// Account drops its password when written.
+type Account struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+func (a Account) MarshalJSON() ([]byte, error) {
+	type account Account
+	a.Password = ""
+	out, err := json.Marshal(account(a))
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Tags is written however it likes.
+type Tags []string
+
+func (t Tags) MarshalJSON() ([]byte, error) {
+	if len(t) == 0 {
+		return nil, errors.New("no tags")
+	}
+	return []byte(`"` + strings.Join(t, ",") + `"`), nil
+}
+
+type Report struct {
+	Level   Level   `json:"level"`
+	Price   Money   `json:"price"`
+	Owner   Account `json:"owner"`
+	Tags    Tags    `json:"tags"`
+	Summary struct {
+		Money
+	} `json:"summary"`
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should document TextMarshaler types as strings, infer what
+	// MarshalJSON methods marshal and leave the others untyped
+	router.GET("/reports/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, Report{})
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var readMethods = []string{http.MethodGet, http.MethodHead}
+
+func main() {
+	router := gin.Default()
+
+	// it should expand routes matching a set of methods (gin >= 1.9)
+	router.Match([]string{http.MethodGet, http.MethodPost}, "/match", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// it should resolve the methods and fold the path
+	v1 := router.Group("/v1")
+	v1.Match(readMethods, "/items/"+":id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func auth(c *gin.Context) {
+	if c.GetHeader("Authorization") == "" {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}
+}
+
+func rateLimit(c *gin.Context) {
+	c.AbortWithStatus(http.StatusTooManyRequests)
+}
+
+func tenant(c *gin.Context) {
+	_ = c.Query("tenant")
+}
+
+func main() {
+	router := gin.New()
+
+	// it should not apply middlewares to routes registered before them
+	router.GET("/public", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// it should analyze every handler of the chain
+	router.GET("/chain", auth, rateLimit, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// it should support group middlewares, copied when the group is created
+	admin := router.Group("/admin", auth)
+	router.Use(rateLimit)
+	admin.Use(tenant)
+	{
+		admin.GET("/users", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+	}
+
+	// it should apply engine middlewares to routes registered after them
+	router.GET("/limited", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// it should expand middlewares spread out of a slice
+	guarded := router.Group("/guarded")
+	mws := []gin.HandlerFunc{auth, tenant}
+	guarded.Use(mws...)
+	guarded.GET("/data", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// it should not leak middlewares onto other engines
+	other := gin.New()
+	other.GET("/other", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
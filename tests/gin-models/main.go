@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Base struct {
+	ID      int64 `json:"id"`
+	Version int
+}
+
+type Timestamps struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Audit struct {
+	Title string `json:"Label"`
+	Note  string
+}
+
+type Notes struct {
+	Label string
+	Note  string
+}
+
+type Owner struct {
+	Name string `json:"name"`
+}
+
+type User struct {
+	Base
+	*Timestamps
+	Audit
+	Notes
+	Owner `json:"owner"`
+
+	Name    string  `json:"name" binding:"required"`
+	Email   *string `json:"email"`
+	Bio     string  `json:"bio,omitempty"`
+	Manager *Owner  `json:"manager,omitempty"`
+	Version string
+	secret  string
+}
+
+type Page struct {
+	Users []User `json:"users"`
+	Next  string `json:"next,omitempty"`
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should promote the fields of embedded structs like encoding/json
+	router.GET("/users/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, User{})
+	})
+
+	// it should require properties per direction
+	router.POST("/users", func(c *gin.Context) {
+		var user User
+		if err := c.ShouldBindJSON(&user); err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		c.JSON(http.StatusCreated, user)
+	})
+
+	// it should share components used in a single direction
+	router.GET("/users", func(c *gin.Context) {
+		c.JSON(http.StatusOK, Page{})
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Pagination struct {
+	Page    int `form:"page,default=1" binding:"min=1"`
+	PerPage int `form:"per_page,default=20" binding:"max=100"`
+}
+
+type Filter struct {
+	Since time.Time `form:"since" time_format:"2006-01-02" time_utc:"1"`
+	Until time.Time `form:"until" time_format:"unix"`
+	At    time.Time `form:"at" time_format:"02/01/2006 15:04"`
+}
+
+type sorting struct {
+	Order string `form:"order,default=asc" binding:"oneof=asc desc"`
+}
+
+type ListUsers struct {
+	Pagination
+	*Filter
+	sorting
+
+	Name string `form:"name"`
+	Role struct {
+		Admin bool `form:"admin,default=false"`
+	}
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should bind the fields of embedded and nested structs
+	router.GET("/users", func(c *gin.Context) {
+		var query ListUsers
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	// it should bind the embedded structs of forms
+	router.POST("/users/search", func(c *gin.Context) {
+		var form struct {
+			Pagination
+			Query string `form:"q,default=*"`
+		}
+		_ = c.Bind(&form)
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
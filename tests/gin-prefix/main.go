@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const version = 2
+
+func ok(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+// it should propagate constants passed to followed functions
+func mount(r *gin.RouterGroup, prefix string) {
+	r.GET(prefix+"/list", ok)
+	r.GET(path.Join(prefix, "items", ":id"), ok)
+}
+
+func main() {
+	router := gin.Default()
+
+	mount(router.Group("/a"), "/one")
+	mount(router.Group("/b"), "/two")
+
+	// it should evaluate pure string-building calls
+	router.GET(fmt.Sprintf("/v%d/items", version), ok)
+	router.GET(strings.Join([]string{"", "joined", "path"}, "/"), ok)
+
+	base := "/base"
+	router.GET(base+"/concat", ok)
+
+	// it should fold paths built up with +=
+	api := "/api"
+	api += "/v1"
+	router.GET(api+"/status", ok)
+
+	// it should fold paths bound in terms of their previous value
+	loop := "/loop"
+	loop = loop + "/v2"
+	router.GET(loop+"/x", ok)
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -41,6 +43,38 @@ func main() {
 		_ = c.BindQuery(&queryB)
 	})
 
+	// it should support array and map query parameters
+	router.GET("/query4", func(c *gin.Context) {
+		_, _ = c.GetQuery("q")
+		_ = c.QueryArray("ids")
+		_, _ = c.GetQueryArray("tags")
+		_ = c.QueryMap("filter")
+		_, _ = c.GetQueryMap("sort")
+	})
+
+	// it should type query parameters bound to struct fields
+	router.GET("/query5", func(c *gin.Context) {
+		var query struct {
+			Page    int               `form:"page" binding:"min=1"`
+			Exact   bool              `form:"exact"`
+			Ratio   float64           `form:"ratio"`
+			Since   time.Time         `form:"since"`
+			Tags    []string          `form:"tags"`
+			Filters map[string]string `form:"filters"`
+		}
+		_ = c.ShouldBindQuery(&query)
+	})
+
+	// it should not choke on fields of types that cannot be bound
+	router.GET("/query6", func(c *gin.Context) {
+		var query struct {
+			Q      string `form:"q"`
+			Done   chan struct{}
+			Format func(string) string
+		}
+		_ = c.ShouldBindQuery(&query)
+	})
+
 	if err := router.Run(":8080"); err != nil {
 		panic(err)
 	}
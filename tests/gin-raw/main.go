@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Event struct {
+	Name string `json:"name" xml:"Name"`
+}
+
+type Batch struct {
+	Events []Event `json:"events"`
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should document bodies decoded from the request body
+	router.POST("/events", func(c *gin.Context) {
+		var event Event
+		if err := json.NewDecoder(c.Request.Body).Decode(&event); err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	// it should document bodies unmarshalled from the raw data
+	router.POST("/batches", func(c *gin.Context) {
+		data, err := c.GetRawData()
+		if err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		var batch Batch
+		_ = json.Unmarshal(data, &batch)
+	})
+
+	// it should document xml bodies read from the request body
+	router.PUT("/events", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+
+		event := &Event{}
+		_ = xml.Unmarshal(body, event)
+	})
+
+	// it should document raw bodies as binary
+	router.POST("/blobs", func(c *gin.Context) {
+		blob, _ := ioutil.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, "application/octet-stream", blob)
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
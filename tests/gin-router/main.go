@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rekki/reveal/tests/gin-router/router"
+)
+
+func main() {
+	// it should follow factories building and returning the engine from
+	// another package
+	r := router.New()
+
+	// it should register routes on the group returned by a factory
+	api, err := router.API(r)
+	if err != nil {
+		panic(err)
+	}
+	api.GET("/items", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	r.GET("/version", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	if err := r.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
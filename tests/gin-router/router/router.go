@@ -0,0 +1,25 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// New builds the engine along with the routes every binary serves.
+func New() *gin.Engine {
+	r := gin.New()
+	r.GET("/health", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+// API registers the versioned routes and returns their group.
+func API(r *gin.Engine) (*gin.RouterGroup, error) {
+	api := r.Group("/api/v1")
+	api.GET("/status", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return api, nil
+}
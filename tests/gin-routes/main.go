@@ -0,0 +1,37 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Error struct {
+	Message string
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should expand routes matching any method
+	router.Any("/any", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// it should support static files and folders
+	router.StaticFile("/favicon.ico", "./resources/favicon.ico")
+	router.Static("/assets", "./assets")
+	router.StaticFS("/more", http.Dir("./more"))
+
+	// it should use NoRoute/NoMethod handlers as default responses
+	router.NoRoute(func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, Error{Message: "not found"})
+	})
+	router.NoMethod(func(c *gin.Context) {
+		c.JSON(http.StatusMethodNotAllowed, Error{Message: "method not allowed"})
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Users struct{}
+
+func (u Users) Mount(r *gin.Engine) {
+	r.GET("/users", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+}
+
+type Orders struct{}
+
+func NewOrders() *Orders {
+	return &Orders{}
+}
+
+func (o *Orders) Routes(r *gin.Engine) {
+	r.GET("/orders", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rekki/reveal/tests/gin-server/api"
+)
+
+type Server struct {
+	router *gin.Engine
+	admin  *gin.RouterGroup
+}
+
+// it should follow methods taking the engine as a parameter
+func (s *Server) routes(r *gin.Engine) {
+	r.GET("/health", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	s.admin = r.Group("/admin")
+	s.adminRoutes()
+}
+
+// it should follow methods using engines/groups stored as fields
+func (s *Server) adminRoutes() {
+	s.admin.GET("/stats", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+}
+
+// it should follow methods on imported struct values and interfaces
+type Router interface {
+	Routes(r *gin.Engine)
+}
+
+func main() {
+	s := &Server{router: gin.Default()}
+	s.routes(s.router)
+
+	users := api.Users{}
+	users.Mount(s.router)
+
+	var orders Router = api.NewOrders()
+	orders.Routes(s.router)
+
+	if err := s.router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
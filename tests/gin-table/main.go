@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type route struct {
+	Method  string
+	Path    string
+	Handler gin.HandlerFunc
+}
+
+func handleA(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+func handleB(c *gin.Context) {
+	c.Status(http.StatusCreated)
+}
+
+var routes = []route{
+	{"GET", "/a", handleA},
+	{Method: http.MethodPost, Path: "/b", Handler: handleB},
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should unroll loops over slices of structs
+	for _, rt := range routes {
+		router.Handle(rt.Method, rt.Path, rt.Handler)
+	}
+
+	// it should unroll loops over arrays of pointers
+	for _, rt := range [...]*route{
+		{"PUT", "/c", handleA},
+	} {
+		router.Handle(rt.Method, rt.Path, rt.Handler)
+	}
+
+	// it should unroll loops over maps
+	reads := map[string]gin.HandlerFunc{
+		"/d": handleA,
+		"/e": handleB,
+	}
+	for path, handler := range reads {
+		router.GET(path, handler)
+	}
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
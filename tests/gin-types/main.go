@@ -0,0 +1,58 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Level int
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+
+type (
+	Stamp    = time.Time
+	Severity = Level
+)
+
+type Record struct {
+	CreatedAt time.Time       `json:"created_at"`
+	Timeout   time.Duration   `json:"timeout"`
+	Payload   []byte          `json:"payload"`
+	Extra     json.RawMessage `json:"extra"`
+	Address   net.IP          `json:"address"`
+	Homepage  url.URL         `json:"homepage"`
+	Balance   *big.Int        `json:"balance"`
+	Nickname  sql.NullString  `json:"nickname"`
+	Score     sql.NullFloat64 `json:"score"`
+	DeletedAt sql.NullTime    `json:"deleted_at"`
+	Signal    complex64       `json:"-"`
+	Wave      complex128      `json:"wave,omitempty"`
+	Digest    [4]int          `json:"digest"`
+	SeenAt    Stamp           `json:"seen_at"`
+	Severity  Severity        `json:"severity"`
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should document well-known types by the way they are serialized,
+	// types without a marshaler (url.URL, sql.Null*) as plain structs, and
+	// aliases as the type they stand for
+	router.GET("/records/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, Record{})
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
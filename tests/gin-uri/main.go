@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserURI struct {
+	// ID of the user
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type OrderURI struct {
+	UserID int64  `uri:"id"`
+	ID     string `uri:"order" binding:"required,uuid"` // ID of the order
+	Kind   string `uri:"kind" binding:"oneof=pending shipped"`
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should type path parameters bound with ShouldBindUri
+	router.GET("/users/:id", func(c *gin.Context) {
+		var uri UserURI
+		if err := c.ShouldBindUri(&uri); err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+		}
+	})
+
+	// it should refine group path parameters with BindUri
+	users := router.Group("/users/:id")
+	{
+		users.GET("/orders/:order/:kind", func(c *gin.Context) {
+			var uri OrderURI
+			_ = c.BindUri(&uri)
+		})
+
+		users.GET("/profile", func(c *gin.Context) {})
+	}
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Address struct {
+	Street string `json:"street" binding:"required"`
+	Zip    string `json:"zip" binding:"len=5,numeric"`
+}
+
+type Signup struct {
+	Email     string            `json:"email" binding:"required,email"`
+	Username  string            `json:"username" binding:"required,alphanum,min=3,max=20"`
+	Website   string            `json:"website" binding:"omitempty,url"`
+	Age       int               `json:"age" binding:"gte=18,lt=130"`
+	Plan      string            `json:"plan" binding:"oneof=free pro"`
+	Birthday  string            `json:"birthday" binding:"datetime=2006-01-02"`
+	Tags      []string          `json:"tags" binding:"max=5,dive,min=2"`
+	Labels    map[string]string `json:"labels" binding:"dive,keys,alpha,endkeys,required"`
+	Addresses []Address         `json:"addresses" binding:"required,dive"`
+	Home      *Address          `json:"home" binding:"required"`
+}
+
+type Headers struct {
+	Token   string `header:"X-Token" binding:"required,uuid"`
+	Retries int    `header:"X-Retries" binding:"min=0,max=3"`
+}
+
+func main() {
+	router := gin.Default()
+
+	// it should translate binding rules into schema constraints
+	router.POST("/signup", func(c *gin.Context) {
+		var signup Signup
+		if err := c.ShouldBindJSON(&signup); err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	// it should translate binding rules into parameter constraints
+	router.GET("/me", func(c *gin.Context) {
+		var headers Headers
+		_ = c.ShouldBindHeader(&headers)
+	})
+
+	if err := router.Run(":8080"); err != nil {
+		panic(err)
+	}
+}
@@ -3,6 +3,7 @@ package tests
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"io/ioutil"
 	"os"
 	"path"
@@ -13,6 +14,8 @@ import (
 	"github.com/rekki/reveal/reveal"
 )
 
+var update = flag.Bool("update", false, "rewrite the openapi3.json files with the current output")
+
 func TestReveal(t *testing.T) {
 	_, currentFilename, _, _ := runtime.Caller(0)
 	currentDirname := path.Dir(currentFilename)
@@ -31,17 +34,27 @@ func TestReveal(t *testing.T) {
 		t.Run(dirname, func(t *testing.T) {
 			t.Parallel()
 
-			expected, err := ioutil.ReadFile(path.Join(dirname, "openapi3.json"))
+			out, err := reveal.Reveal(context.Background(), dirname)
 			if err != nil {
 				panic(err)
 			}
 
-			out, err := reveal.Reveal(context.Background(), dirname)
+			// the git infos change with every commit
+			out.Info.Version = "git hash"
+			out.Info.Description = ""
+
+			outjson, err := json.MarshalIndent(out, "", "  ")
 			if err != nil {
 				panic(err)
 			}
 
-			outjson, err := json.Marshal(out)
+			if *update {
+				if err := ioutil.WriteFile(path.Join(dirname, "openapi3.json"), append(outjson, '\n'), 0644); err != nil {
+					panic(err)
+				}
+			}
+
+			expected, err := ioutil.ReadFile(path.Join(dirname, "openapi3.json"))
 			if err != nil {
 				panic(err)
 			}